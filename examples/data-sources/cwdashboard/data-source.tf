@@ -33,6 +33,19 @@ data "cwdashboard" "this" {
     data.cwdashboard_text_widget.this.json,
     data.cwdashboard_graph_widget.this.json,
   ]
+
+  variables = [
+    {
+      type          = "property"
+      property      = "InstanceId"
+      input_type    = "select"
+      id            = "instance"
+      label         = "Instance"
+      default_value = "i-0123456789abcdef0"
+      search        = "{AWS/EC2,InstanceId} MetricName=\"CPUUtilization\""
+      populate_from = "InstanceId"
+    },
+  ]
 }
 
 # to create dashboard, use AWS Terraform Provider with the dashboard JSON
@@ -40,3 +53,16 @@ resource "aws_cloudwatch_dashboard" "this" {
   dashboard_name = "test-dashboard"
   dashboard_body = data.cwdashboard.this.json
 }
+
+# widgets can also be placed with an ASCII-art grid, where every letter is a widget
+data "cwdashboard" "layout" {
+  layout            = <<-EOT
+    TTTT
+    GGG.
+  EOT
+  layout_row_height = 6
+  layout_widgets = {
+    T = data.cwdashboard_text_widget.this.json
+    G = data.cwdashboard_graph_widget.this.json
+  }
+}
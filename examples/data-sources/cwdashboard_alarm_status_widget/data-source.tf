@@ -0,0 +1,26 @@
+data "cwdashboard_alarm_status_widget" "this" {
+  width  = 24
+  height = 3
+  title  = "Alarms"
+
+  alarms = [
+    "arn:aws:cloudwatch:us-east-1:123456789012:alarm:high-cpu",
+    "arn:aws:cloudwatch:us-east-1:123456789012:alarm:high-latency",
+  ]
+  sort_by = "stateUpdatedTimestamp"
+  states  = ["ALARM", "INSUFFICIENT_DATA"]
+}
+
+data "cwdashboard" "this" {
+  start           = "-PT7D"
+  period_override = "auto"
+  widgets = [
+    data.cwdashboard_alarm_status_widget.this.json,
+  ]
+}
+
+# to create dashboard, use AWS Terraform Provider with the dashboard JSON
+resource "aws_cloudwatch_dashboard" "this" {
+  dashboard_name = "test-dashboard"
+  dashboard_body = data.cwdashboard.this.json
+}
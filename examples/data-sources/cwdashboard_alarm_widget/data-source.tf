@@ -0,0 +1,26 @@
+data "cwdashboard_alarm_widget" "this" {
+  width  = 12
+  height = 6
+  title  = "High CPU"
+
+  alarm_arn = "arn:aws:cloudwatch:us-east-1:123456789012:alarm:high-cpu"
+
+  left_y_axis = {
+    min = 0
+    max = 100
+  }
+}
+
+data "cwdashboard" "this" {
+  start           = "-PT7D"
+  period_override = "auto"
+  widgets = [
+    data.cwdashboard_alarm_widget.this.json,
+  ]
+}
+
+# to create dashboard, use AWS Terraform Provider with the dashboard JSON
+resource "aws_cloudwatch_dashboard" "this" {
+  dashboard_name = "test-dashboard"
+  dashboard_body = data.cwdashboard.this.json
+}
@@ -0,0 +1,44 @@
+data "cwdashboard_text_widget" "title" {
+  markdown = "# Orders"
+  width    = 8
+  height   = 1
+}
+
+data "cwdashboard_alarm_status_widget" "orders" {
+  alarms = [
+    "arn:aws:cloudwatch:us-east-1:123456789012:alarm:orders-error-rate",
+  ]
+  width  = 8
+  height = 3
+}
+
+data "cwdashboard_log_widget" "errors" {
+  log_group_names = ["/aws/lambda/orders"]
+  query           = "fields @timestamp, @message | filter @message like /ERROR/ | limit 20"
+  width           = 16
+  height          = 4
+}
+
+# the title and the alarms are stacked next to the logs
+data "cwdashboard_column" "summary" {
+  width = 8
+  widgets = [
+    data.cwdashboard_text_widget.title.json,
+    data.cwdashboard_alarm_status_widget.orders.json,
+  ]
+}
+
+data "cwdashboard" "this" {
+  start           = "-PT7D"
+  period_override = "auto"
+  widgets = [
+    data.cwdashboard_column.summary.json,
+    data.cwdashboard_log_widget.errors.json,
+  ]
+}
+
+# to create dashboard, use AWS Terraform Provider with the dashboard JSON
+resource "aws_cloudwatch_dashboard" "this" {
+  dashboard_name = "test-dashboard"
+  dashboard_body = data.cwdashboard.this.json
+}
@@ -0,0 +1,31 @@
+data "cwdashboard_custom_widget" "this" {
+  width  = 12
+  height = 6
+
+  title    = "Deployments"
+  endpoint = "arn:aws:lambda:us-east-1:123456789012:function:render-deployments"
+  params = jsonencode({
+    table = "deployments"
+    limit = 10
+  })
+
+  update_on = {
+    refresh    = true
+    resize     = false
+    time_range = true
+  }
+}
+
+data "cwdashboard" "this" {
+  start           = "-PT7D"
+  period_override = "auto"
+  widgets = [
+    data.cwdashboard_custom_widget.this.json,
+  ]
+}
+
+# to create dashboard, use AWS Terraform Provider with the dashboard JSON
+resource "aws_cloudwatch_dashboard" "this" {
+  dashboard_name = "test-dashboard"
+  dashboard_body = data.cwdashboard.this.json
+}
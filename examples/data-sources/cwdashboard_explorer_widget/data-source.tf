@@ -0,0 +1,44 @@
+data "cwdashboard_explorer_widget" "this" {
+  width  = 24
+  height = 15
+  title  = "Web Fleet CPU Utilization"
+
+  metrics = [
+    {
+      metric_name   = "CPUUtilization"
+      resource_type = "AWS::EC2::Instance"
+      statistic     = "Average"
+    },
+  ]
+  labels = [
+    {
+      key   = "Service"
+      value = "web"
+    },
+  ]
+  aggregate_by = {
+    key      = "InstanceType"
+    function = "MAX"
+  }
+  split_by = "AvailabilityZone"
+  period   = 300
+
+  view            = "timeSeries"
+  widgets_per_row = 2
+  rows_per_page   = 50
+  legend_position = "bottom"
+}
+
+data "cwdashboard" "this" {
+  start           = "-PT7D"
+  period_override = "auto"
+  widgets = [
+    data.cwdashboard_explorer_widget.this.json,
+  ]
+}
+
+# to create dashboard, use AWS Terraform Provider with the dashboard JSON
+resource "aws_cloudwatch_dashboard" "this" {
+  dashboard_name = "test-dashboard"
+  dashboard_body = data.cwdashboard.this.json
+}
@@ -17,6 +17,22 @@ data "cwdashboard_graph_widget" "this" {
   left = [
     data.cwdashboard_metric.this.json,
   ]
+
+  left_annotations = [
+    {
+      value = 80
+      label = "CPU threshold"
+      color = "#d62728"
+      fill  = "above"
+    },
+  ]
+
+  vertical_annotations = [
+    {
+      value = "2024-01-01T00:00:00Z"
+      label = "Deployment"
+    },
+  ]
 }
 
 data "cwdashboard" "this" {
@@ -0,0 +1,25 @@
+data "cwdashboard_log_widget" "this" {
+  width  = 24
+  height = 6
+  title  = "Application Errors"
+
+  log_group_names = [
+    "/aws/lambda/my-function",
+  ]
+  query = "fields @timestamp, @message | filter @message like /ERROR/ | sort @timestamp desc | limit 20"
+  view  = "table"
+}
+
+data "cwdashboard" "this" {
+  start           = "-PT7D"
+  period_override = "auto"
+  widgets = [
+    data.cwdashboard_log_widget.this.json,
+  ]
+}
+
+# to create dashboard, use AWS Terraform Provider with the dashboard JSON
+resource "aws_cloudwatch_dashboard" "this" {
+  dashboard_name = "test-dashboard"
+  dashboard_body = data.cwdashboard.this.json
+}
@@ -8,6 +8,20 @@ data "cwdashboard_metric" "this" {
   period    = 60
 }
 
+# the same metric from another account and region, to compare them in one graph
+data "cwdashboard_metric" "staging" {
+  metric_name = "CPUUtilization"
+  namespace   = "AWS/EC2"
+  dimensions_map = {
+    InstanceId = "i-0fedcba9876543210"
+  }
+  statistic = "Average"
+  period    = 60
+  account   = "210987654321"
+  region    = "eu-west-1"
+  label     = "staging"
+}
+
 data "cwdashboard_graph_widget" "this" {
   width  = 24
   height = 6
@@ -16,6 +30,7 @@ data "cwdashboard_graph_widget" "this" {
 
   left = [
     data.cwdashboard_metric.this.json,
+    data.cwdashboard_metric.staging.json,
   ]
 }
 
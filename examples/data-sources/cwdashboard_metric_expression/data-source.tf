@@ -11,11 +11,49 @@ data "cwdashboard_metric" "this" {
 data "cwdashboard_metric_expression" "this" {
   expression = "ANOMALY_DETECTION_BAND(m1, 2)"
   label      = "Anomaly Detection Band"
+  id         = "band"
+  period     = 60
   using_metrics = {
     m1 = data.cwdashboard_metric.this.json
   }
 }
 
+# expressions can use other expressions, e.g. the error rate of the sum of the errors
+data "cwdashboard_metric" "target_5xx" {
+  metric_name = "HTTPCode_Target_5XX_Count"
+  namespace   = "AWS/ApplicationELB"
+  statistic   = "Sum"
+}
+
+data "cwdashboard_metric" "elb_5xx" {
+  metric_name = "HTTPCode_ELB_5XX_Count"
+  namespace   = "AWS/ApplicationELB"
+  statistic   = "Sum"
+}
+
+data "cwdashboard_metric" "requests" {
+  metric_name = "RequestCount"
+  namespace   = "AWS/ApplicationELB"
+  statistic   = "Sum"
+}
+
+data "cwdashboard_metric_expression" "errors" {
+  expression = "m1 + m2"
+  using_metrics = {
+    m1 = data.cwdashboard_metric.target_5xx.json
+    m2 = data.cwdashboard_metric.elb_5xx.json
+  }
+}
+
+data "cwdashboard_metric_expression" "error_rate" {
+  expression = "errors / requests * 100"
+  label      = "Error Rate (%)"
+  using_metrics = {
+    errors   = data.cwdashboard_metric_expression.errors.json
+    requests = data.cwdashboard_metric.requests.json
+  }
+}
+
 data "cwdashboard_graph_widget" "this" {
   width  = 24
   height = 6
@@ -27,11 +65,20 @@ data "cwdashboard_graph_widget" "this" {
   ]
 }
 
+data "cwdashboard_graph_widget" "error_rate" {
+  title = "ALB Error Rate"
+
+  left = [
+    data.cwdashboard_metric_expression.error_rate.json,
+  ]
+}
+
 data "cwdashboard" "this" {
   start           = "-PT7D"
   period_override = "auto"
   widgets = [
     data.cwdashboard_graph_widget.this.json,
+    data.cwdashboard_graph_widget.error_rate.json,
   ]
 }
 
@@ -40,3 +87,4 @@ resource "aws_cloudwatch_dashboard" "this" {
   dashboard_name = "test-dashboard"
   dashboard_body = data.cwdashboard.this.json
 }
+
@@ -0,0 +1,64 @@
+data "cwdashboard_metric" "cpu" {
+  metric_name = "CPUUtilization"
+  namespace   = "AWS/EC2"
+  dimensions_map = {
+    InstanceId = "i-0123456789abcdef0"
+  }
+  statistic = "Average"
+}
+
+data "cwdashboard_metric" "network_in" {
+  metric_name = "NetworkIn"
+  namespace   = "AWS/EC2"
+  dimensions_map = {
+    InstanceId = "i-0123456789abcdef0"
+  }
+  statistic = "Sum"
+}
+
+data "cwdashboard_metric" "network_out" {
+  metric_name = "NetworkOut"
+  namespace   = "AWS/EC2"
+  dimensions_map = {
+    InstanceId = "i-0123456789abcdef0"
+  }
+  statistic = "Sum"
+}
+
+data "cwdashboard_graph_widget" "cpu" {
+  title = "CPU Utilization"
+  left  = [data.cwdashboard_metric.cpu.json]
+}
+
+data "cwdashboard_graph_widget" "network_in" {
+  title = "Network In"
+  left  = [data.cwdashboard_metric.network_in.json]
+}
+
+data "cwdashboard_graph_widget" "network_out" {
+  title = "Network Out"
+  left  = [data.cwdashboard_metric.network_out.json]
+}
+
+# the three graphs share the row at equal widths, at the default height of graphs
+data "cwdashboard_row" "this" {
+  widgets = [
+    data.cwdashboard_graph_widget.cpu.json,
+    data.cwdashboard_graph_widget.network_in.json,
+    data.cwdashboard_graph_widget.network_out.json,
+  ]
+}
+
+data "cwdashboard" "this" {
+  start           = "-PT7D"
+  period_override = "auto"
+  widgets = [
+    data.cwdashboard_row.this.json,
+  ]
+}
+
+# to create dashboard, use AWS Terraform Provider with the dashboard JSON
+resource "aws_cloudwatch_dashboard" "this" {
+  dashboard_name = "test-dashboard"
+  dashboard_body = data.cwdashboard.this.json
+}
@@ -0,0 +1,64 @@
+data "cwdashboard_metric" "cpu" {
+  metric_name = "CPUUtilization"
+  namespace   = "AWS/EC2"
+  dimensions_map = {
+    InstanceId = "i-0123456789abcdef0"
+  }
+  statistic = "Average"
+}
+
+data "cwdashboard_metric" "db_connections" {
+  metric_name = "DatabaseConnections"
+  namespace   = "AWS/RDS"
+  dimensions_map = {
+    DBInstanceIdentifier = "my-database"
+  }
+  statistic = "Maximum"
+}
+
+data "cwdashboard_graph_widget" "cpu" {
+  title  = "CPU Utilization"
+  width  = 12
+  height = 6
+  left   = [data.cwdashboard_metric.cpu.json]
+}
+
+data "cwdashboard_graph_widget" "db_connections" {
+  title  = "Database Connections"
+  width  = 12
+  height = 6
+  left   = [data.cwdashboard_metric.db_connections.json]
+}
+
+data "cwdashboard_section" "compute" {
+  title = "Compute"
+  widgets = [
+    data.cwdashboard_graph_widget.cpu.json,
+  ]
+}
+
+data "cwdashboard_section" "database" {
+  title = "Database"
+  widgets = [
+    data.cwdashboard_graph_widget.db_connections.json,
+  ]
+}
+
+# the table of contents lists the sections at the top of the dashboard
+data "cwdashboard" "this" {
+  start           = "-PT7D"
+  period_override = "auto"
+  table_of_contents = {
+    title = "Overview"
+  }
+  widgets = [
+    data.cwdashboard_section.compute.json,
+    data.cwdashboard_section.database.json,
+  ]
+}
+
+# to create dashboard, use AWS Terraform Provider with the dashboard JSON
+resource "aws_cloudwatch_dashboard" "this" {
+  dashboard_name = "test-dashboard"
+  dashboard_body = data.cwdashboard.this.json
+}
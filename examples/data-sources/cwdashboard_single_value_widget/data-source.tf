@@ -0,0 +1,37 @@
+data "cwdashboard_metric" "this" {
+  metric_name = "CPUUtilization"
+  namespace   = "AWS/EC2"
+  dimensions_map = {
+    InstanceId = "i-0123456789abcdef0"
+  }
+  statistic = "Average"
+  period    = 60
+}
+
+data "cwdashboard_single_value_widget" "this" {
+  width  = 6
+  height = 3
+
+  title = "EC2 CPU Utilization"
+
+  metrics = [
+    data.cwdashboard_metric.this.json,
+  ]
+
+  sparkline                   = true
+  single_value_full_precision = true
+}
+
+data "cwdashboard" "this" {
+  start           = "-PT7D"
+  period_override = "auto"
+  widgets = [
+    data.cwdashboard_single_value_widget.this.json,
+  ]
+}
+
+# to create dashboard, use AWS Terraform Provider with the dashboard JSON
+resource "aws_cloudwatch_dashboard" "this" {
+  dashboard_name = "test-dashboard"
+  dashboard_body = data.cwdashboard.this.json
+}
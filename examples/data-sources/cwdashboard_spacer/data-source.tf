@@ -0,0 +1,42 @@
+data "cwdashboard_metric" "cpu" {
+  metric_name = "CPUUtilization"
+  namespace   = "AWS/EC2"
+  dimensions_map = {
+    InstanceId = "i-0123456789abcdef0"
+  }
+  statistic = "Average"
+}
+
+data "cwdashboard_graph_widget" "cpu" {
+  title = "CPU Utilization"
+  width = 12
+  left  = [data.cwdashboard_metric.cpu.json]
+}
+
+data "cwdashboard_text_widget" "notes" {
+  markdown = "See the runbook for the thresholds"
+  width    = 6
+  height   = 6
+}
+
+# leaves a gap between the graph and the notes
+data "cwdashboard_spacer" "gap" {
+  width  = 6
+  height = 6
+}
+
+data "cwdashboard" "this" {
+  start           = "-PT7D"
+  period_override = "auto"
+  widgets = [
+    data.cwdashboard_graph_widget.cpu.json,
+    data.cwdashboard_spacer.gap.json,
+    data.cwdashboard_text_widget.notes.json,
+  ]
+}
+
+# to create dashboard, use AWS Terraform Provider with the dashboard JSON
+resource "aws_cloudwatch_dashboard" "this" {
+  dashboard_name = "test-dashboard"
+  dashboard_body = data.cwdashboard.this.json
+}
@@ -1,15 +1,25 @@
 data "cwdashboard_text_widget" "this" {
   markdown   = "# Hello, World!"
   background = "#000000"
-  width      = 24
+  width      = 18
   height     = 2
 }
 
+# pinned to the top-right corner, other widgets are placed around it
+data "cwdashboard_text_widget" "legend" {
+  markdown = "**Legend**: red means the threshold is exceeded"
+  width    = 6
+  height   = 2
+  x        = 18
+  y        = 0
+}
+
 data "cwdashboard" "this" {
   start           = "-PT7D"
   period_override = "auto"
   widgets = [
     data.cwdashboard_text_widget.this.json,
+    data.cwdashboard_text_widget.legend.json,
   ]
 }
 
@@ -0,0 +1,245 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource = &alarmStatusWidgetDataSource{}
+)
+
+type alarmStatusWidgetDataSource struct {
+}
+
+func NewAlarmStatusWidgetDataSource() func() datasource.DataSource {
+	return func() datasource.DataSource {
+		return &alarmStatusWidgetDataSource{}
+	}
+}
+
+func (d *alarmStatusWidgetDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_alarm_status_widget"
+}
+
+func (d *alarmStatusWidgetDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"alarms": schema.ListAttribute{
+				Description: "An array of alarm ARNs to include in the widget. The array can have 1-100 ARNs.",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"sort_by": schema.StringAttribute{
+				Description: "Specifies how to sort the alarms in the widget. " +
+					"Valid Values: `default` | `stateUpdatedTimestamp` | `timestamp`",
+				Optional: true,
+			},
+			"states": schema.ListAttribute{
+				Description: "Use this field to filter the list of alarms displayed in the widget to only those alarms currently in the specified states. " +
+					"Valid Values: `ALARM` | `INSUFFICIENT_DATA` | `OK`",
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"title": schema.StringAttribute{
+				Description: "The title to be displayed for the alarm widget",
+				Optional:    true,
+			},
+			"width": schema.Int32Attribute{
+				Description: "Width of the widget, in a grid of 24 units wide. Defaults to an even share of its row, see `columns` of `cwdashboard`",
+				Optional:    true,
+			},
+			"height": schema.Int32Attribute{
+				Description: "Height of the widget. Defaults to `3`",
+				Optional:    true,
+			},
+			"enabled": schema.BoolAttribute{
+				Description: "Whether the widget is in the dashboard. Defaults to `true`. The other widgets are laid out as if a disabled widget was not there",
+				Optional:    true,
+			},
+			"x": schema.Int32Attribute{
+				Description: "The horizontal position of the widget, in a grid of 24 units wide. Must be set together with `y`. When omitted, the widget is placed automatically",
+				Optional:    true,
+			},
+			"y": schema.Int32Attribute{
+				Description: "The vertical position of the widget. Must be set together with `x`. When omitted, the widget is placed automatically",
+				Optional:    true,
+			},
+
+			"json": schema.StringAttribute{
+				Description: "The settings of the widget",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type alarmStatusWidgetDataSourceModel struct {
+	Alarms  []types.String `tfsdk:"alarms"`
+	SortBy  types.String   `tfsdk:"sort_by"`
+	States  []types.String `tfsdk:"states"`
+	Title   types.String   `tfsdk:"title"`
+	Width   types.Int32    `tfsdk:"width"`
+	Height  types.Int32    `tfsdk:"height"`
+	X       types.Int32    `tfsdk:"x"`
+	Y       types.Int32    `tfsdk:"y"`
+	Enabled types.Bool     `tfsdk:"enabled"`
+
+	Json types.String `tfsdk:"json"`
+}
+
+const (
+	alarmStatusWidgetMaxAlarms = 100
+)
+
+var (
+	// arn:<partition>:cloudwatch:<region>:<account>:alarm:<name>
+	alarmArnPattern = regexp.MustCompile(`^arn:aws[a-z-]*:cloudwatch:[a-z0-9-]+:[0-9]{12}:alarm:.+$`)
+)
+
+func (d *alarmStatusWidgetDataSourceModel) Validate() error {
+	if err := validateWidgetSize(d.Width, d.Height); err != nil {
+		return err
+	}
+	if err := validateWidgetPosition(d.X, d.Y); err != nil {
+		return err
+	}
+
+	if len(d.Alarms) == 0 || len(d.Alarms) > alarmStatusWidgetMaxAlarms {
+		return fmt.Errorf("alarms must contain between 1 and %d alarm ARNs, got: %d", alarmStatusWidgetMaxAlarms, len(d.Alarms))
+	}
+
+	for _, alarm := range d.Alarms {
+		if !alarmArnPattern.MatchString(alarm.ValueString()) {
+			return fmt.Errorf("invalid alarm ARN: %s", alarm.ValueString())
+		}
+	}
+
+	if !d.SortBy.IsNull() {
+		sortBy := d.SortBy.ValueString()
+		validSortBy := map[string]bool{
+			"default":               true,
+			"stateUpdatedTimestamp": true,
+			"timestamp":             true,
+		}
+		if !validSortBy[sortBy] {
+			return fmt.Errorf("sort_by must be one of 'default', 'stateUpdatedTimestamp', or 'timestamp', got: %s", sortBy)
+		}
+	}
+
+	validStates := map[string]bool{
+		"ALARM":             true,
+		"INSUFFICIENT_DATA": true,
+		"OK":                true,
+	}
+	for _, state := range d.States {
+		if !validStates[state.ValueString()] {
+			return fmt.Errorf("states must only contain 'ALARM', 'INSUFFICIENT_DATA', or 'OK', got: %s", state.ValueString())
+		}
+	}
+
+	return nil
+}
+
+type alarmStatusWidgetDataSourceSettings struct {
+	Type    string   `json:"type"`
+	Alarms  []string `json:"alarms"`
+	SortBy  string   `json:"sort_by,omitempty"`
+	States  []string `json:"states,omitempty"`
+	Title   string   `json:"title,omitempty"`
+	Width   int32    `json:"width"`
+	Height  int32    `json:"height"`
+	X       *int32   `json:"x,omitempty"`
+	Y       *int32   `json:"y,omitempty"`
+	Enabled *bool    `json:"enabled,omitempty"`
+}
+
+const (
+	typeAlarmStatusWidget = "alarm_status"
+
+	defaultAlarmStatusWidgetHeight = 3
+)
+
+func (d *alarmStatusWidgetDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state alarmStatusWidgetDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := state.Validate(); err != nil {
+		resp.Diagnostics.AddError("invalid settings", err.Error())
+		return
+	}
+
+	alarms := make([]string, len(state.Alarms))
+	for i, alarm := range state.Alarms {
+		alarms[i] = alarm.ValueString()
+	}
+
+	var states []string
+	for _, s := range state.States {
+		states = append(states, s.ValueString())
+	}
+
+	settings := alarmStatusWidgetDataSourceSettings{
+		Type:    typeAlarmStatusWidget,
+		Alarms:  alarms,
+		SortBy:  state.SortBy.ValueString(),
+		States:  states,
+		Title:   state.Title.ValueString(),
+		Width:   state.Width.ValueInt32(),
+		Height:  state.Height.ValueInt32(),
+		X:       state.X.ValueInt32Pointer(),
+		Y:       state.Y.ValueInt32Pointer(),
+		Enabled: state.Enabled.ValueBoolPointer(),
+	}
+
+	b, err := json.Marshal(settings)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to marshal widget settings", err.Error())
+		return
+	}
+
+	tflog.Info(ctx, "alarm status widget settings", map[string]interface{}{
+		"settings": string(b),
+	})
+
+	state.Json = types.StringValue(string(b))
+
+	stateDiags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(stateDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (w alarmStatusWidgetDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Context) (CWDashboardBodyWidget, error) {
+	cwWidget := CWDashboardBodyWidget{
+		Type:   "alarm",
+		Width:  w.Width,
+		Height: widgetHeightOrDefault(w.Height, defaultAlarmStatusWidgetHeight),
+		Properties: CWDashboardBodyWidgetPropertyAlarm{
+			Alarms: w.Alarms,
+			SortBy: w.SortBy,
+			States: w.States,
+			Title:  w.Title,
+		},
+	}
+
+	pinWidget(&cwWidget, w.X, w.Y)
+
+	tflog.Debug(ctx, "built alarm status widget", map[string]interface{}{
+		"widget": cwWidget,
+	})
+
+	return cwWidget, nil
+}
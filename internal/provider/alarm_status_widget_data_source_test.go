@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"github.com/tj/assert"
+)
+
+func TestAlarmStatusWidgetDataSourceModel_Validate(t *testing.T) {
+	validArn := types.StringValue("arn:aws:cloudwatch:us-east-1:123456789012:alarm:high-cpu")
+
+	tests := []struct {
+		name    string
+		model   alarmStatusWidgetDataSourceModel
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "valid complete model",
+			model: alarmStatusWidgetDataSourceModel{
+				Alarms: []types.String{validArn},
+				SortBy: types.StringValue("stateUpdatedTimestamp"),
+				States: []types.String{types.StringValue("ALARM"), types.StringValue("OK")},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid model with china partition ARN",
+			model: alarmStatusWidgetDataSourceModel{
+				Alarms: []types.String{types.StringValue("arn:aws-cn:cloudwatch:cn-north-1:123456789012:alarm:high-cpu")},
+			},
+			wantErr: false,
+		},
+		{
+			name:    "no alarms",
+			model:   alarmStatusWidgetDataSourceModel{},
+			wantErr: true,
+			errMsg:  "alarms must contain between 1 and 100 alarm ARNs, got: 0",
+		},
+		{
+			name: "too many alarms",
+			model: alarmStatusWidgetDataSourceModel{
+				Alarms: func() []types.String {
+					alarms := make([]types.String, 101)
+					for i := range alarms {
+						alarms[i] = validArn
+					}
+					return alarms
+				}(),
+			},
+			wantErr: true,
+			errMsg:  "alarms must contain between 1 and 100 alarm ARNs, got: 101",
+		},
+		{
+			name: "invalid alarm ARN",
+			model: alarmStatusWidgetDataSourceModel{
+				Alarms: []types.String{types.StringValue("arn:aws:sns:us-east-1:123456789012:topic")},
+			},
+			wantErr: true,
+			errMsg:  "invalid alarm ARN: arn:aws:sns:us-east-1:123456789012:topic",
+		},
+		{
+			name: "invalid sort_by",
+			model: alarmStatusWidgetDataSourceModel{
+				Alarms: []types.String{validArn},
+				SortBy: types.StringValue("name"),
+			},
+			wantErr: true,
+			errMsg:  "sort_by must be one of 'default', 'stateUpdatedTimestamp', or 'timestamp', got: name",
+		},
+		{
+			name: "invalid state",
+			model: alarmStatusWidgetDataSourceModel{
+				Alarms: []types.String{validArn},
+				States: []types.String{types.StringValue("alarm")},
+			},
+			wantErr: true,
+			errMsg:  "states must only contain 'ALARM', 'INSUFFICIENT_DATA', or 'OK', got: alarm",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.model.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Validate() error = nil, want error")
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Validate() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestAlarmStatusWidgetDataSourceSettings_ToCWDashboardBodyWidget(t *testing.T) {
+	input := alarmStatusWidgetDataSourceSettings{
+		Alarms: []string{"arn:aws:cloudwatch:us-east-1:123456789012:alarm:high-cpu"},
+		SortBy: "stateUpdatedTimestamp",
+		States: []string{"ALARM"},
+		Title:  "Alarms",
+		Width:  24,
+		Height: 3,
+	}
+
+	actual, err := input.ToCWDashboardBodyWidget(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, CWDashboardBodyWidget{
+		Type:   "alarm",
+		X:      0,
+		Y:      0,
+		Width:  24,
+		Height: 3,
+		Properties: CWDashboardBodyWidgetPropertyAlarm{
+			Alarms: []string{"arn:aws:cloudwatch:us-east-1:123456789012:alarm:high-cpu"},
+			SortBy: "stateUpdatedTimestamp",
+			States: []string{"ALARM"},
+			Title:  "Alarms",
+		},
+	}, actual)
+}
@@ -0,0 +1,245 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource = &alarmWidgetDataSource{}
+)
+
+type alarmWidgetDataSource struct {
+}
+
+func NewAlarmWidgetDataSource() func() datasource.DataSource {
+	return func() datasource.DataSource {
+		return &alarmWidgetDataSource{}
+	}
+}
+
+func (d *alarmWidgetDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_alarm_widget"
+}
+
+func (d *alarmWidgetDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"alarm_arn": schema.StringAttribute{
+				Description: "The ARN of the alarm whose metric and threshold are displayed. CloudWatch supports only one alarm per widget.",
+				Required:    true,
+			},
+			"left_y_axis": schema.SingleNestedAttribute{
+				Description: "Settings for the left Y axis",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"label": schema.StringAttribute{
+						Description: "The label",
+						Optional:    true,
+					},
+					"max": schema.Float64Attribute{
+						Description: "The maximum value",
+						Optional:    true,
+					},
+					"min": schema.Float64Attribute{
+						Description: "The minimum value",
+						Optional:    true,
+					},
+					"show_units": schema.BoolAttribute{
+						Description: "Whether to show units",
+						Optional:    true,
+					},
+				},
+			},
+			"region": schema.StringAttribute{
+				Description: "The region the alarm is located in. Defaults to the region in `alarm_arn`",
+				Optional:    true,
+			},
+			"title": schema.StringAttribute{
+				Description: "Title for the graph",
+				Optional:    true,
+			},
+			"width": schema.Int32Attribute{
+				Description: "Width of the widget, in a grid of 24 units wide. Defaults to an even share of its row, see `columns` of `cwdashboard`",
+				Optional:    true,
+			},
+			"height": schema.Int32Attribute{
+				Description: "Height of the widget. Defaults to `6`",
+				Optional:    true,
+			},
+			"enabled": schema.BoolAttribute{
+				Description: "Whether the widget is in the dashboard. Defaults to `true`. The other widgets are laid out as if a disabled widget was not there",
+				Optional:    true,
+			},
+			"x": schema.Int32Attribute{
+				Description: "The horizontal position of the widget, in a grid of 24 units wide. Must be set together with `y`. When omitted, the widget is placed automatically",
+				Optional:    true,
+			},
+			"y": schema.Int32Attribute{
+				Description: "The vertical position of the widget. Must be set together with `x`. When omitted, the widget is placed automatically",
+				Optional:    true,
+			},
+
+			"json": schema.StringAttribute{
+				Description: "The settings of the widget",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type alarmWidgetDataSourceModel struct {
+	AlarmArn  types.String                     `tfsdk:"alarm_arn"`
+	LeftYAxis *graphWidgetYAxisDataSourceModel `tfsdk:"left_y_axis"`
+	Region    types.String                     `tfsdk:"region"`
+	Title     types.String                     `tfsdk:"title"`
+	Width     types.Int32                      `tfsdk:"width"`
+	Height    types.Int32                      `tfsdk:"height"`
+	X         types.Int32                      `tfsdk:"x"`
+	Y         types.Int32                      `tfsdk:"y"`
+	Enabled   types.Bool                       `tfsdk:"enabled"`
+
+	Json types.String `tfsdk:"json"`
+}
+
+func (d *alarmWidgetDataSourceModel) Validate() error {
+	if err := validateWidgetSize(d.Width, d.Height); err != nil {
+		return err
+	}
+	if err := validateWidgetPosition(d.X, d.Y); err != nil {
+		return err
+	}
+
+	if !alarmArnPattern.MatchString(d.AlarmArn.ValueString()) {
+		return fmt.Errorf("invalid alarm ARN: %s", d.AlarmArn.ValueString())
+	}
+
+	return nil
+}
+
+type alarmWidgetDataSourceSettings struct {
+	Type      string                              `json:"type"`
+	AlarmArn  string                              `json:"alarm_arn"`
+	LeftYAxis *graphWidgetYAxisDataSourceSettings `json:"left_y_axis,omitempty"`
+	Region    string                              `json:"region,omitempty"`
+	Title     string                              `json:"title,omitempty"`
+	Width     int32                               `json:"width"`
+	Height    int32                               `json:"height"`
+	X         *int32                              `json:"x,omitempty"`
+	Y         *int32                              `json:"y,omitempty"`
+	Enabled   *bool                               `json:"enabled,omitempty"`
+}
+
+const (
+	typeAlarmWidget = "alarm"
+
+	defaultAlarmWidgetHeight = 6
+)
+
+func (d *alarmWidgetDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state alarmWidgetDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := state.Validate(); err != nil {
+		resp.Diagnostics.AddError("invalid settings", err.Error())
+		return
+	}
+
+	settings := alarmWidgetDataSourceSettings{
+		Type:     typeAlarmWidget,
+		AlarmArn: state.AlarmArn.ValueString(),
+		Region:   state.Region.ValueString(),
+		Title:    state.Title.ValueString(),
+		Width:    state.Width.ValueInt32(),
+		Height:   state.Height.ValueInt32(),
+		X:        state.X.ValueInt32Pointer(),
+		Y:        state.Y.ValueInt32Pointer(),
+		Enabled:  state.Enabled.ValueBoolPointer(),
+	}
+
+	if state.LeftYAxis != nil {
+		settings.LeftYAxis = &graphWidgetYAxisDataSourceSettings{
+			Label:     state.LeftYAxis.Label.ValueString(),
+			Max:       state.LeftYAxis.Max.ValueFloat64Pointer(),
+			Min:       state.LeftYAxis.Min.ValueFloat64Pointer(),
+			ShowUnits: state.LeftYAxis.ShowUnits.ValueBool(),
+		}
+	}
+
+	b, err := json.Marshal(settings)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to marshal widget settings", err.Error())
+		return
+	}
+
+	tflog.Info(ctx, "alarm widget settings", map[string]interface{}{
+		"settings": string(b),
+	})
+
+	state.Json = types.StringValue(string(b))
+
+	stateDiags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(stateDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (w alarmWidgetDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Context) (CWDashboardBodyWidget, error) {
+	// NOTE: the widget json may be written by hand, so the ARN is checked again here
+	if !alarmArnPattern.MatchString(w.AlarmArn) {
+		return CWDashboardBodyWidget{}, fmt.Errorf("invalid alarm ARN: %s", w.AlarmArn)
+	}
+
+	region := w.Region
+	if region == "" {
+		// arn:partition:cloudwatch:region:account-id:alarm:alarm-name
+		region = strings.Split(w.AlarmArn, ":")[3]
+	}
+
+	var yAxis *CWDashboardBodyWidgetPropertyMetricYAxis
+	if w.LeftYAxis != nil {
+		yAxis = &CWDashboardBodyWidgetPropertyMetricYAxis{
+			Left: &CWDashboardBodyWidgetPropertyMetricYAxisSide{
+				Label:     w.LeftYAxis.Label,
+				Max:       w.LeftYAxis.Max,
+				Min:       w.LeftYAxis.Min,
+				ShowUnits: w.LeftYAxis.ShowUnits,
+			},
+		}
+	}
+
+	cwWidget := CWDashboardBodyWidget{
+		Type:   "metric",
+		Width:  w.Width,
+		Height: widgetHeightOrDefault(w.Height, defaultAlarmWidgetHeight),
+		Properties: CWDashboardBodyWidgetPropertyAlarmGraph{
+			Annotations: &CWDashboardBodyWidgetPropertyMetricAnnotations{
+				Alarms: []string{w.AlarmArn},
+			},
+			Region: region,
+			Title:  w.Title,
+			View:   "timeSeries",
+			YAxis:  yAxis,
+		},
+	}
+
+	pinWidget(&cwWidget, w.X, w.Y)
+
+	tflog.Debug(ctx, "built alarm widget", map[string]interface{}{
+		"widget": cwWidget,
+	})
+
+	return cwWidget, nil
+}
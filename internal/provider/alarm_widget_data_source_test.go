@@ -0,0 +1,136 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"github.com/tj/assert"
+)
+
+func TestAlarmWidgetDataSourceModel_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		model   alarmWidgetDataSourceModel
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "valid alarm ARN",
+			model: alarmWidgetDataSourceModel{
+				AlarmArn: types.StringValue("arn:aws:cloudwatch:us-east-1:123456789012:alarm:high-cpu"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid GovCloud alarm ARN",
+			model: alarmWidgetDataSourceModel{
+				AlarmArn: types.StringValue("arn:aws-us-gov:cloudwatch:us-gov-west-1:123456789012:alarm:high-cpu"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid alarm ARN",
+			model: alarmWidgetDataSourceModel{
+				AlarmArn: types.StringValue("high-cpu"),
+			},
+			wantErr: true,
+			errMsg:  "invalid alarm ARN: high-cpu",
+		},
+		{
+			name: "composite alarm ARN without account",
+			model: alarmWidgetDataSourceModel{
+				AlarmArn: types.StringValue("arn:aws:cloudwatch:us-east-1::alarm:high-cpu"),
+			},
+			wantErr: true,
+			errMsg:  "invalid alarm ARN: arn:aws:cloudwatch:us-east-1::alarm:high-cpu",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.model.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Validate() error = nil, want error")
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Validate() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestAlarmWidgetDataSourceSettings_ToCWDashboardBodyWidget(t *testing.T) {
+	t.Run("should render the alarm as an annotation without metrics", func(t *testing.T) {
+		input := alarmWidgetDataSourceSettings{
+			AlarmArn: "arn:aws:cloudwatch:us-east-1:123456789012:alarm:high-cpu",
+			LeftYAxis: &graphWidgetYAxisDataSourceSettings{
+				Min: ptr(float64(0)),
+				Max: ptr(float64(100)),
+			},
+			Region: "us-east-1",
+			Title:  "High CPU",
+			Width:  12,
+			Height: 6,
+		}
+
+		actual, err := input.ToCWDashboardBodyWidget(context.Background())
+		require.NoError(t, err)
+
+		b, err := json.Marshal(actual)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"type": "metric",
+			"x": 0,
+			"y": 0,
+			"width": 12,
+			"height": 6,
+			"properties": {
+				"annotations": {"alarms": ["arn:aws:cloudwatch:us-east-1:123456789012:alarm:high-cpu"]},
+				"region": "us-east-1",
+				"title": "High CPU",
+				"view": "timeSeries",
+				"yAxis": {"left": {"min": 0, "max": 100}}
+			}
+		}`, string(b))
+	})
+
+	t.Run("should use the region of the alarm ARN when the region is not set", func(t *testing.T) {
+		input := alarmWidgetDataSourceSettings{
+			AlarmArn: "arn:aws:cloudwatch:ap-northeast-1:123456789012:alarm:high-cpu",
+			Width:    12,
+			Height:   6,
+		}
+
+		actual, err := input.ToCWDashboardBodyWidget(context.Background())
+		require.NoError(t, err)
+
+		b, err := json.Marshal(actual.Properties)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{
+			"annotations": {"alarms": ["arn:aws:cloudwatch:ap-northeast-1:123456789012:alarm:high-cpu"]},
+			"region": "ap-northeast-1",
+			"view": "timeSeries"
+		}`, string(b))
+	})
+
+	t.Run("should fail with an invalid alarm ARN", func(t *testing.T) {
+		input := alarmWidgetDataSourceSettings{
+			AlarmArn: "high-cpu",
+			Width:    12,
+			Height:   6,
+		}
+
+		_, err := input.ToCWDashboardBodyWidget(context.Background())
+		assert.EqualError(t, err, "invalid alarm ARN: high-cpu")
+	})
+}
@@ -0,0 +1,171 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource = &columnDataSource{}
+)
+
+type columnDataSource struct {
+}
+
+func NewColumnDataSource() func() datasource.DataSource {
+	return func() datasource.DataSource {
+		return &columnDataSource{}
+	}
+}
+
+func (d *columnDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_column"
+}
+
+func (d *columnDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Stacks widgets on top of each other. Every widget takes the width of the column. " +
+			"The column can be used in `widgets` of `cwdashboard`, `cwdashboard_row`, `cwdashboard_column` and `cwdashboard_section` like a widget.",
+		Attributes: map[string]schema.Attribute{
+			"widgets": schema.ListAttribute{
+				Description: "The widgets in the column, from top to bottom",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"width": schema.Int32Attribute{
+				Description: "Width of the column, in a grid of 24 units wide. Defaults to the width of the widest widget, or to an even share of its row when no widget has a width. Ignored when the column is in a row",
+				Optional:    true,
+			},
+			"enabled": schema.BoolAttribute{
+				Description: "Whether the column is in the dashboard. Defaults to `true`. The other widgets are laid out as if a disabled column was not there",
+				Optional:    true,
+			},
+
+			"json": schema.StringAttribute{
+				Description: "The settings of the column",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type columnDataSourceModel struct {
+	Widgets []types.String `tfsdk:"widgets"`
+	Width   types.Int32    `tfsdk:"width"`
+	Enabled types.Bool     `tfsdk:"enabled"`
+
+	Json types.String `tfsdk:"json"`
+}
+
+func (d *columnDataSourceModel) Validate() error {
+	if err := validateGroupWidgets(d.Widgets); err != nil {
+		return err
+	}
+
+	if !d.Width.IsNull() {
+		if width := d.Width.ValueInt32(); width < 1 || width > MAX_WIDTH {
+			return fmt.Errorf("width must be between 1 and %d, got: %d", MAX_WIDTH, width)
+		}
+	}
+
+	return nil
+}
+
+type columnDataSourceSettings struct {
+	Type    string            `json:"type"`
+	Widgets []json.RawMessage `json:"widgets"`
+	Width   *int32            `json:"width,omitempty"`
+	Enabled *bool             `json:"enabled,omitempty"`
+
+	// Children holds the parsed settings of Widgets
+	Children []interface{} `json:"-"`
+}
+
+const (
+	typeColumn = "column"
+)
+
+func (d *columnDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state columnDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := state.Validate(); err != nil {
+		resp.Diagnostics.AddError("invalid settings", err.Error())
+		return
+	}
+
+	widgets := make([]json.RawMessage, len(state.Widgets))
+	for i, w := range state.Widgets {
+		widgets[i] = json.RawMessage(w.ValueString())
+	}
+
+	settings := columnDataSourceSettings{
+		Type:    typeColumn,
+		Widgets: widgets,
+		Width:   state.Width.ValueInt32Pointer(),
+		Enabled: state.Enabled.ValueBoolPointer(),
+	}
+
+	b, err := json.Marshal(settings)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to marshal column settings", err.Error())
+		return
+	}
+
+	tflog.Info(ctx, "column settings", map[string]interface{}{
+		"settings": string(b),
+	})
+
+	state.Json = types.StringValue(string(b))
+
+	stateDiags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(stateDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (w columnDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Context) (CWDashboardBodyWidget, error) {
+	children, err := buildGroupChildren(ctx, widgetGroupTypeColumn, w.Children)
+	if err != nil {
+		return CWDashboardBodyWidget{}, err
+	}
+
+	var width int32
+	if w.Width != nil {
+		width = *w.Width
+	} else {
+		for _, child := range children {
+			if child.Width > width {
+				width = child.Width
+			}
+		}
+	}
+
+	column := CWDashboardBodyWidget{
+		Type:     widgetGroupTypeColumn,
+		Children: children,
+	}
+	// NOTE: when none of the widgets has a width, the column is arranged once the width is given by where it is placed
+	if width > 0 {
+		if err := resizeWidget(&column, width); err != nil {
+			return CWDashboardBodyWidget{}, err
+		}
+	}
+
+	tflog.Debug(ctx, "built column", map[string]interface{}{
+		"widget": column,
+	})
+
+	return column, nil
+}
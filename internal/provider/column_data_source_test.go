@@ -0,0 +1,116 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"github.com/tj/assert"
+)
+
+func TestColumnDataSourceModel_Validate(t *testing.T) {
+	widget := types.StringValue(`{"type":"text","markdown":"# Hello","width":8,"height":2}`)
+
+	tests := []struct {
+		name    string
+		model   columnDataSourceModel
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "valid complete model",
+			model: columnDataSourceModel{
+				Widgets: []types.String{widget, widget},
+				Width:   types.Int32Value(8),
+			},
+			wantErr: false,
+		},
+		{
+			name:    "no widgets",
+			model:   columnDataSourceModel{},
+			wantErr: true,
+			errMsg:  "widgets must contain at least one widget",
+		},
+		{
+			name: "too wide",
+			model: columnDataSourceModel{
+				Widgets: []types.String{widget},
+				Width:   types.Int32Value(25),
+			},
+			wantErr: true,
+			errMsg:  "width must be between 1 and 24, got: 25",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.model.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Validate() error = nil, want error %v", tt.errMsg)
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Validate() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestColumnDataSourceSettings_ToCWDashboardBodyWidget(t *testing.T) {
+	t.Run("should stack the widgets at the width of the widest one", func(t *testing.T) {
+		input := columnDataSourceSettings{
+			Children: []interface{}{
+				textWidgetDataSourceSettings{Width: 6, Height: 2, Markdown: "a"},
+				graphWidgetDataSourceSettings{Width: 8, Height: 6},
+			},
+		}
+
+		actual, err := input.ToCWDashboardBodyWidget(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, widgetGroupTypeColumn, actual.Type)
+		assert.Equal(t, int32(8), actual.Width)
+		assert.Equal(t, int32(8), actual.Height)
+		require.Len(t, actual.Children, 2)
+		assert.Equal(t, []widgetRect{
+			{widgetPosition{X: 0, Y: 0}, widgetSize{Width: 8, Height: 2}},
+			{widgetPosition{X: 0, Y: 2}, widgetSize{Width: 8, Height: 6}},
+		}, []widgetRect{
+			{widgetPosition{X: actual.Children[0].X, Y: actual.Children[0].Y}, widgetSize{Width: actual.Children[0].Width, Height: actual.Children[0].Height}},
+			{widgetPosition{X: actual.Children[1].X, Y: actual.Children[1].Y}, widgetSize{Width: actual.Children[1].Width, Height: actual.Children[1].Height}},
+		})
+	})
+
+	t.Run("should arrange a nested row at the width of the column", func(t *testing.T) {
+		input := columnDataSourceSettings{
+			Width: ptr(int32(12)),
+			Children: []interface{}{
+				textWidgetDataSourceSettings{Width: 6, Height: 2, Markdown: "a"},
+				rowDataSourceSettings{
+					Children: []interface{}{
+						graphWidgetDataSourceSettings{Width: 24, Height: 6},
+						graphWidgetDataSourceSettings{Width: 24, Height: 6},
+					},
+				},
+			},
+		}
+
+		actual, err := input.ToCWDashboardBodyWidget(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, int32(8), actual.Height)
+		row := actual.Children[1]
+		assert.Equal(t, int32(12), row.Width)
+		assert.Equal(t, int32(2), row.Y)
+		assert.Equal(t, int32(6), row.Children[0].Width)
+		assert.Equal(t, int32(6), row.Children[1].X)
+		assert.Equal(t, int32(6), row.Children[1].Width)
+	})
+}
@@ -0,0 +1,254 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"regexp"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource = &customWidgetDataSource{}
+)
+
+type customWidgetDataSource struct {
+}
+
+func NewCustomWidgetDataSource() func() datasource.DataSource {
+	return func() datasource.DataSource {
+		return &customWidgetDataSource{}
+	}
+}
+
+func (d *customWidgetDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_custom_widget"
+}
+
+func (d *customWidgetDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"endpoint": schema.StringAttribute{
+				Description: "The ARN of the Lambda function that renders the widget",
+				Required:    true,
+			},
+			"params": schema.StringAttribute{
+				Description: "A JSON object passed to the Lambda function, e.g. `jsonencode({ table = \"deployments\" })`",
+				Optional:    true,
+			},
+			"title": schema.StringAttribute{
+				Description: "Title for the widget",
+				Optional:    true,
+			},
+			"update_on": schema.SingleNestedAttribute{
+				Description: "When the widget is refreshed by calling the Lambda function again",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"refresh": schema.BoolAttribute{
+						Description: "Whether to refresh when the dashboard is refreshed. Defaults to `true`",
+						Optional:    true,
+					},
+					"resize": schema.BoolAttribute{
+						Description: "Whether to refresh when the widget is resized. Defaults to `true`",
+						Optional:    true,
+					},
+					"time_range": schema.BoolAttribute{
+						Description: "Whether to refresh when the time range of the dashboard changes. Defaults to `true`",
+						Optional:    true,
+					},
+				},
+			},
+			"width": schema.Int32Attribute{
+				Description: "Width of the widget, in a grid of 24 units wide. Defaults to an even share of its row, see `columns` of `cwdashboard`",
+				Optional:    true,
+			},
+			"height": schema.Int32Attribute{
+				Description: "Height of the widget. Defaults to `6`",
+				Optional:    true,
+			},
+			"enabled": schema.BoolAttribute{
+				Description: "Whether the widget is in the dashboard. Defaults to `true`. The other widgets are laid out as if a disabled widget was not there",
+				Optional:    true,
+			},
+			"x": schema.Int32Attribute{
+				Description: "The horizontal position of the widget, in a grid of 24 units wide. Must be set together with `y`. When omitted, the widget is placed automatically",
+				Optional:    true,
+			},
+			"y": schema.Int32Attribute{
+				Description: "The vertical position of the widget. Must be set together with `x`. When omitted, the widget is placed automatically",
+				Optional:    true,
+			},
+
+			"json": schema.StringAttribute{
+				Description: "The settings of the widget",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type customWidgetUpdateOnDataSourceModel struct {
+	Refresh   types.Bool `tfsdk:"refresh"`
+	Resize    types.Bool `tfsdk:"resize"`
+	TimeRange types.Bool `tfsdk:"time_range"`
+}
+
+type customWidgetDataSourceModel struct {
+	Endpoint types.String                         `tfsdk:"endpoint"`
+	Params   types.String                         `tfsdk:"params"`
+	Title    types.String                         `tfsdk:"title"`
+	UpdateOn *customWidgetUpdateOnDataSourceModel `tfsdk:"update_on"`
+	Width    types.Int32                          `tfsdk:"width"`
+	Height   types.Int32                          `tfsdk:"height"`
+	X        types.Int32                          `tfsdk:"x"`
+	Y        types.Int32                          `tfsdk:"y"`
+	Enabled  types.Bool                           `tfsdk:"enabled"`
+
+	Json types.String `tfsdk:"json"`
+}
+
+var (
+	// arn:<partition>:lambda:<region>:<account>:function:<name>[:<version or alias>]
+	lambdaFunctionArnPattern = regexp.MustCompile(`^arn:aws[a-z-]*:lambda:[a-z0-9-]+:[0-9]{12}:function:[A-Za-z0-9_-]+(:[A-Za-z0-9_$-]+)?$`)
+)
+
+func (d *customWidgetDataSourceModel) Validate() error {
+	if err := validateWidgetSize(d.Width, d.Height); err != nil {
+		return err
+	}
+	if err := validateWidgetPosition(d.X, d.Y); err != nil {
+		return err
+	}
+
+	if !lambdaFunctionArnPattern.MatchString(d.Endpoint.ValueString()) {
+		return fmt.Errorf("endpoint must be a Lambda function ARN, got: %s", d.Endpoint.ValueString())
+	}
+
+	if !d.Params.IsNull() {
+		var params map[string]interface{}
+		if err := json.Unmarshal([]byte(d.Params.ValueString()), &params); err != nil || params == nil {
+			return fmt.Errorf("params must be a JSON object, got: %s", d.Params.ValueString())
+		}
+	}
+
+	return nil
+}
+
+type customWidgetUpdateOnDataSourceSettings struct {
+	Refresh   *bool `json:"refresh,omitempty"`
+	Resize    *bool `json:"resize,omitempty"`
+	TimeRange *bool `json:"time_range,omitempty"`
+}
+
+type customWidgetDataSourceSettings struct {
+	Type     string                                  `json:"type"`
+	Endpoint string                                  `json:"endpoint"`
+	Params   json.RawMessage                         `json:"params,omitempty"`
+	Title    string                                  `json:"title,omitempty"`
+	UpdateOn *customWidgetUpdateOnDataSourceSettings `json:"update_on,omitempty"`
+	Width    int32                                   `json:"width"`
+	Height   int32                                   `json:"height"`
+	X        *int32                                  `json:"x,omitempty"`
+	Y        *int32                                  `json:"y,omitempty"`
+	Enabled  *bool                                   `json:"enabled,omitempty"`
+}
+
+const (
+	typeCustomWidget = "custom"
+
+	defaultCustomWidgetHeight = 6
+)
+
+func (d *customWidgetDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state customWidgetDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := state.Validate(); err != nil {
+		resp.Diagnostics.AddError("invalid settings", err.Error())
+		return
+	}
+
+	settings := customWidgetDataSourceSettings{
+		Type:     typeCustomWidget,
+		Endpoint: state.Endpoint.ValueString(),
+		Title:    state.Title.ValueString(),
+		Width:    state.Width.ValueInt32(),
+		Height:   state.Height.ValueInt32(),
+		X:        state.X.ValueInt32Pointer(),
+		Y:        state.Y.ValueInt32Pointer(),
+		Enabled:  state.Enabled.ValueBoolPointer(),
+	}
+
+	if !state.Params.IsNull() {
+		settings.Params = json.RawMessage(state.Params.ValueString())
+	}
+	if state.UpdateOn != nil {
+		settings.UpdateOn = &customWidgetUpdateOnDataSourceSettings{
+			Refresh:   state.UpdateOn.Refresh.ValueBoolPointer(),
+			Resize:    state.UpdateOn.Resize.ValueBoolPointer(),
+			TimeRange: state.UpdateOn.TimeRange.ValueBoolPointer(),
+		}
+	}
+
+	b, err := json.Marshal(settings)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to marshal widget settings", err.Error())
+		return
+	}
+
+	tflog.Info(ctx, "custom widget settings", map[string]interface{}{
+		"settings": string(b),
+	})
+
+	state.Json = types.StringValue(string(b))
+
+	stateDiags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(stateDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (w customWidgetDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Context) (CWDashboardBodyWidget, error) {
+	// NOTE: the widget json may be written by hand, so the ARN is checked again here
+	if !lambdaFunctionArnPattern.MatchString(w.Endpoint) {
+		return CWDashboardBodyWidget{}, fmt.Errorf("endpoint must be a Lambda function ARN, got: %s", w.Endpoint)
+	}
+
+	var updateOn *CWDashboardBodyWidgetPropertyCustomUpdateOn
+	if w.UpdateOn != nil {
+		updateOn = &CWDashboardBodyWidgetPropertyCustomUpdateOn{
+			Refresh:   w.UpdateOn.Refresh,
+			Resize:    w.UpdateOn.Resize,
+			TimeRange: w.UpdateOn.TimeRange,
+		}
+	}
+
+	cwWidget := CWDashboardBodyWidget{
+		Type:   "custom",
+		Width:  w.Width,
+		Height: widgetHeightOrDefault(w.Height, defaultCustomWidgetHeight),
+		Properties: CWDashboardBodyWidgetPropertyCustom{
+			Endpoint: w.Endpoint,
+			Params:   w.Params,
+			Title:    w.Title,
+			UpdateOn: updateOn,
+		},
+	}
+
+	pinWidget(&cwWidget, w.X, w.Y)
+
+	tflog.Debug(ctx, "built custom widget", map[string]interface{}{
+		"widget": cwWidget,
+	})
+
+	return cwWidget, nil
+}
@@ -0,0 +1,132 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tj/assert"
+)
+
+func TestCustomWidgetDataSourceModel_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		model   customWidgetDataSourceModel
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "valid complete model",
+			model: customWidgetDataSourceModel{
+				Endpoint: types.StringValue("arn:aws:lambda:us-east-1:123456789012:function:render-deployments"),
+				Params:   types.StringValue(`{"table":"deployments","limit":10}`),
+				Title:    types.StringValue("Deployments"),
+				UpdateOn: &customWidgetUpdateOnDataSourceModel{
+					Refresh:   types.BoolValue(true),
+					Resize:    types.BoolValue(false),
+					TimeRange: types.BoolValue(true),
+				},
+				Width:  types.Int32Value(12),
+				Height: types.Int32Value(6),
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid endpoint with alias",
+			model: customWidgetDataSourceModel{
+				Endpoint: types.StringValue("arn:aws:lambda:us-east-1:123456789012:function:render-deployments:live"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid endpoint",
+			model: customWidgetDataSourceModel{
+				Endpoint: types.StringValue("arn:aws:cloudwatch:us-east-1:123456789012:alarm:high-cpu"),
+			},
+			wantErr: true,
+			errMsg:  "endpoint must be a Lambda function ARN, got: arn:aws:cloudwatch:us-east-1:123456789012:alarm:high-cpu",
+		},
+		{
+			name: "params is not JSON",
+			model: customWidgetDataSourceModel{
+				Endpoint: types.StringValue("arn:aws:lambda:us-east-1:123456789012:function:render-deployments"),
+				Params:   types.StringValue("table=deployments"),
+			},
+			wantErr: true,
+			errMsg:  "params must be a JSON object, got: table=deployments",
+		},
+		{
+			name: "params is not a JSON object",
+			model: customWidgetDataSourceModel{
+				Endpoint: types.StringValue("arn:aws:lambda:us-east-1:123456789012:function:render-deployments"),
+				Params:   types.StringValue(`["deployments"]`),
+			},
+			wantErr: true,
+			errMsg:  `params must be a JSON object, got: ["deployments"]`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.model.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Validate() error = nil, want error %v", tt.errMsg)
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Validate() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestCustomWidgetDataSourceSettings_ToCWDashboardBodyWidget(t *testing.T) {
+	t.Run("should build the custom widget from its json", func(t *testing.T) {
+		data := `{
+			"type": "custom",
+			"endpoint": "arn:aws:lambda:us-east-1:123456789012:function:render-deployments",
+			"params": {"table": "deployments", "limit": 10},
+			"title": "Deployments",
+			"update_on": {"refresh": true, "resize": false},
+			"width": 12,
+			"height": 6
+		}`
+
+		var input customWidgetDataSourceSettings
+		assert.NoError(t, json.Unmarshal([]byte(data), &input))
+
+		cwWidget, err := input.ToCWDashboardBodyWidget(context.TODO())
+		assert.NoError(t, err)
+
+		assert.Equal(t, "custom", cwWidget.Type)
+		assert.Equal(t, int32(12), cwWidget.Width)
+		assert.Equal(t, int32(6), cwWidget.Height)
+
+		b, err := json.Marshal(cwWidget.Properties)
+		assert.NoError(t, err)
+		assert.JSONEq(t, `{
+			"endpoint": "arn:aws:lambda:us-east-1:123456789012:function:render-deployments",
+			"params": {"table": "deployments", "limit": 10},
+			"title": "Deployments",
+			"updateOn": {"refresh": true, "resize": false}
+		}`, string(b))
+	})
+
+	t.Run("should reject an invalid endpoint", func(t *testing.T) {
+		input := customWidgetDataSourceSettings{
+			Endpoint: "render-deployments",
+			Width:    12,
+			Height:   6,
+		}
+
+		_, err := input.ToCWDashboardBodyWidget(context.TODO())
+		assert.EqualError(t, err, "endpoint must be a Lambda function ARN, got: render-deployments")
+	})
+}
@@ -4,19 +4,36 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"slices"
+	"strings"
 )
 
-/*
-	variables, metrics explorer not yet supported
-*/
-
 // https://docs.aws.amazon.com/AmazonCloudWatch/latest/APIReference/CloudWatch-Dashboard-Body-Structure.html
 type CWDashboardBody struct {
-	Widgets []CWDashboardBodyWidget `json:"widgets"`
-	// Variables []CWDashboardBodyVariable `json:"variables"`
-	Start          string `json:"start,omitempty"`
-	End            string `json:"end,omitempty"`
-	PeriodOverride string `json:"periodOverride,omitempty"`
+	Widgets        []CWDashboardBodyWidget   `json:"widgets"`
+	Variables      []CWDashboardBodyVariable `json:"variables,omitempty"`
+	Start          string                    `json:"start,omitempty"`
+	End            string                    `json:"end,omitempty"`
+	PeriodOverride string                    `json:"periodOverride,omitempty"`
+}
+
+type CWDashboardBodyVariable struct {
+	Type         string                         `json:"type"`
+	Property     string                         `json:"property,omitempty"`
+	Pattern      string                         `json:"pattern,omitempty"`
+	InputType    string                         `json:"inputType"`
+	Id           string                         `json:"id"`
+	Label        string                         `json:"label,omitempty"`
+	DefaultValue string                         `json:"defaultValue,omitempty"`
+	Visible      bool                           `json:"visible"`
+	Search       string                         `json:"search,omitempty"`
+	PopulateFrom string                         `json:"populateFrom,omitempty"`
+	Values       []CWDashboardBodyVariableValue `json:"values,omitempty"`
+}
+
+type CWDashboardBodyVariableValue struct {
+	Value string `json:"value"`
+	Label string `json:"label,omitempty"`
 }
 
 type CWDashboardBodyWidget struct {
@@ -26,6 +43,13 @@ type CWDashboardBodyWidget struct {
 	Width      int32       `json:"width"`
 	Height     int32       `json:"height"`
 	Properties interface{} `json:"properties"`
+	// Pinned is set when the position is given explicitly, so that the layout keeps it
+	Pinned bool `json:"-"`
+	// Children is set on rows, columns and sections, with the positions relative to the group
+	Children []CWDashboardBodyWidget `json:"-"`
+	// Index is the position of the widget in the widgets of the dashboard, so that errors of the layout point at the widget.
+	// It is kept through the disabled widgets being dropped
+	Index int `json:"-"`
 }
 
 type CWDashboardBodyWidgetPropertyText struct {
@@ -36,21 +60,24 @@ type CWDashboardBodyWidgetPropertyText struct {
 type CWDashboardBodyWidgetPropertyMetric struct {
 	// NOTE: Widget level settings are not supported yet
 	// AccountId string `json:"accountId,omitempty"`
-	// NOTE: annotations are not supported yet
-	// Annotations *CWDashboardBodyWidgetPropertyMetricAnnotations `json:"annotations,omitempty"`
-	LiveData  bool                                       `json:"liveData,omitempty"`
-	Legend    *CWDashboardBodyWidgetPropertyMetricLegend `json:"legend,omitempty"`
-	Metrics   [][]interface{}                            `json:"metrics"`
-	Period    int32                                      `json:"period,omitempty"`
-	Region    string                                     `json:"region"`
-	Sparkline bool                                       `json:"sparkline,omitempty"`
-	Stacked   bool                                       `json:"stacked,omitempty"`
-	Stat      string                                     `json:"stat,omitempty"`
-	Table     *CWDashboardBodyWidgetPropertyMetricTable  `json:"table,omitempty"`
-	Timezone  string                                     `json:"timezone,omitempty"`
-	Title     string                                     `json:"title,omitempty"`
-	View      string                                     `json:"view,omitempty"`
-	YAxis     *CWDashboardBodyWidgetPropertyMetricYAxis  `json:"yAxis,omitempty"`
+	Annotations              *CWDashboardBodyWidgetPropertyMetricAnnotations `json:"annotations,omitempty"`
+	Labels                   *CWDashboardBodyWidgetPropertyMetricLabels      `json:"labels,omitempty"`
+	LiveData                 bool                                            `json:"liveData,omitempty"`
+	Legend                   *CWDashboardBodyWidgetPropertyMetricLegend      `json:"legend,omitempty"`
+	Metrics                  [][]interface{}                                 `json:"metrics"`
+	Period                   int32                                           `json:"period,omitempty"`
+	Region                   string                                          `json:"region"`
+	SetPeriodToTimeRange     bool                                            `json:"setPeriodToTimeRange,omitempty"`
+	SingleValueFullPrecision bool                                            `json:"singleValueFullPrecision,omitempty"`
+	Sparkline                bool                                            `json:"sparkline,omitempty"`
+	Stacked                  bool                                            `json:"stacked,omitempty"`
+	Stat                     string                                          `json:"stat,omitempty"`
+	Table                    *CWDashboardBodyWidgetPropertyMetricTable       `json:"table,omitempty"`
+	Timezone                 string                                          `json:"timezone,omitempty"`
+	Title                    string                                          `json:"title,omitempty"`
+	Trend                    *bool                                           `json:"trend,omitempty"`
+	View                     string                                          `json:"view,omitempty"`
+	YAxis                    *CWDashboardBodyWidgetPropertyMetricYAxis       `json:"yAxis,omitempty"`
 }
 
 type CWDashboardBodyWidgetPropertyMetricAnnotations struct {
@@ -64,8 +91,23 @@ type CWDashboardBodyWidgetPropertyMetricAnnotationsHorizontal struct {
 	Label   string  `json:"label,omitempty"`
 	Color   string  `json:"color,omitempty"`
 	Fill    string  `json:"fill,omitempty"`
-	Visible bool    `json:"visible,omitempty"`
+	Visible *bool   `json:"visible,omitempty"`
 	YAxis   string  `json:"yAxis,omitempty"`
+	// EndValue turns the annotation into a band between Value and EndValue
+	EndValue *float64 `json:"-"`
+}
+
+func (a CWDashboardBodyWidgetPropertyMetricAnnotationsHorizontal) MarshalJSON() ([]byte, error) {
+	type annotation CWDashboardBodyWidgetPropertyMetricAnnotationsHorizontal
+	if a.EndValue == nil {
+		return json.Marshal(annotation(a))
+	}
+
+	// NOTE: a band is expressed as a pair of annotations, the second one only holding the end value
+	return json.Marshal([]interface{}{
+		annotation(a),
+		map[string]interface{}{"value": *a.EndValue},
+	})
 }
 
 type CWDashboardBodyWidgetPropertyMetricAnnotationsVertical struct {
@@ -73,7 +115,26 @@ type CWDashboardBodyWidgetPropertyMetricAnnotationsVertical struct {
 	Label   string `json:"label,omitempty"`
 	Color   string `json:"color,omitempty"`
 	Fill    string `json:"fill,omitempty"`
-	Visible bool   `json:"visible,omitempty"`
+	Visible *bool  `json:"visible,omitempty"`
+	// EndValue turns the annotation into a band between Value and EndValue
+	EndValue string `json:"-"`
+}
+
+func (a CWDashboardBodyWidgetPropertyMetricAnnotationsVertical) MarshalJSON() ([]byte, error) {
+	type annotation CWDashboardBodyWidgetPropertyMetricAnnotationsVertical
+	if a.EndValue == "" {
+		return json.Marshal(annotation(a))
+	}
+
+	// NOTE: a band is expressed as a pair of annotations, the second one only holding the end value
+	return json.Marshal([]interface{}{
+		annotation(a),
+		map[string]interface{}{"value": a.EndValue},
+	})
+}
+
+type CWDashboardBodyWidgetPropertyMetricLabels struct {
+	Visible bool `json:"visible"`
 }
 
 type CWDashboardBodyWidgetPropertyMetricLegend struct {
@@ -86,16 +147,16 @@ type CWDashboardBodyWidgetPropertyMetricYAxis struct {
 }
 
 type CWDashboardBodyWidgetPropertyMetricYAxisSide struct {
-	Label     string  `json:"label,omitempty"`
-	Min       float64 `json:"min,omitempty"`
-	Max       float64 `json:"max,omitempty"`
-	ShowUnits bool    `json:"showUnits,omitempty"`
+	Label     string   `json:"label,omitempty"`
+	Min       *float64 `json:"min,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+	ShowUnits bool     `json:"showUnits,omitempty"`
 }
 
 type CWDashboardBodyWidgetPropertyMetricTable struct {
 	Layout             string   `json:"layout,omitempty"`
 	StickySummary      bool     `json:"stickySummary,omitempty"`
-	ShowTimeSeriesData bool     `json:"showTimeSeriesData,omitempty"`
+	ShowTimeSeriesData *bool    `json:"showTimeSeriesData,omitempty"`
 	SummaryColumns     []string `json:"summaryColumns,omitempty"`
 }
 
@@ -104,77 +165,137 @@ type CWDashboardBodyWidgetPropertyLog struct {
 	Region    string `json:"region"`
 	Title     string `json:"title,omitempty"`
 	Query     string `json:"query"`
+	Stacked   bool   `json:"stacked,omitempty"`
 	View      string `json:"view,omitempty"`
 }
 
 type CWDashboardBodyWidgetPropertyExplorer struct {
-	// AggregateBy   *CWDashboardBodyWidgetPropertyExplorerAggregateBy `json:"aggregateBy,omitempty"`
-	// Labels        []CWDashboardBodyWidgetPropertyExplorerLabel      `json:"labels"`
-	// Metrics       []CWDashboardBodyWidgetPropertyExplorerMetric     `json:"metrics"`
-	Period  int32  `json:"period,omitempty"`
-	SplitBy string `json:"splitBy,omitempty"`
-	Title   string `json:"title,omitempty"`
-	// WidgetOptions *CWDashboardBodyWidgetPropertyExplorerOptions     `json:"widgetOptions,omitempty"`
-}
-
-// type CWDashboardBodyWidgetPropertyExplorerAggregateBy struct {
-// 	Key  string `json:"key"`
-// 	Func string `json:"func"`
-// }
-
-// type CWDashboardBodyWidgetPropertyExplorerLabel struct {
-// 	Key   string `json:"key"`
-// 	Value string `json:"value,omitempty"`
-// }
-
-// type CWDashboardBodyWidgetPropertyExplorerMetric struct {
-// 	MetricName   string `json:"metricName"`
-// 	ResourceType string `json:"resourceType"`
-// 	Stat         string `json:"stat"`
-// }
-
-// type CWDashboardBodyWidgetPropertyExplorerOptions struct {
-// 	Legend        *CWDashboardBodyWidgetPropertyMetricLegend `json:"legend,omitempty"`
-// 	RowsPerPage   int                                        `json:"rowsPerPage,omitempty"`
-// 	Stacked       bool                                       `json:"stacked,omitempty"`
-// 	View          string                                     `json:"view,omitempty"`
-// 	WidgetsPerRow int                                        `json:"widgetsPerRow,omitempty"`
-// }
-
-// Alarm widget properties are planned for future implementation
-// type CWDashboardBodyWidgetPropertyAlarm struct {
-//     Alarms  []string `json:"alarms"`
-//     SortBy  string   `json:"sortBy,omitempty"`
-//     States  []string `json:"states,omitempty"`
-//     Title   string   `json:"title,omitempty"`
-// }
+	AggregateBy   *CWDashboardBodyWidgetPropertyExplorerAggregateBy `json:"aggregateBy,omitempty"`
+	Labels        []CWDashboardBodyWidgetPropertyExplorerLabel      `json:"labels"`
+	Metrics       []CWDashboardBodyWidgetPropertyExplorerMetric     `json:"metrics"`
+	Period        int32                                             `json:"period,omitempty"`
+	Region        string                                            `json:"region,omitempty"`
+	SplitBy       string                                            `json:"splitBy,omitempty"`
+	Title         string                                            `json:"title,omitempty"`
+	WidgetOptions *CWDashboardBodyWidgetPropertyExplorerOptions     `json:"widgetOptions,omitempty"`
+}
+
+type CWDashboardBodyWidgetPropertyExplorerAggregateBy struct {
+	Key  string `json:"key"`
+	Func string `json:"func"`
+}
+
+type CWDashboardBodyWidgetPropertyExplorerLabel struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+}
+
+type CWDashboardBodyWidgetPropertyExplorerMetric struct {
+	MetricName   string `json:"metricName"`
+	ResourceType string `json:"resourceType"`
+	Stat         string `json:"stat"`
+}
+
+type CWDashboardBodyWidgetPropertyExplorerOptions struct {
+	Legend        *CWDashboardBodyWidgetPropertyMetricLegend `json:"legend,omitempty"`
+	RowsPerPage   int32                                      `json:"rowsPerPage,omitempty"`
+	Stacked       bool                                       `json:"stacked,omitempty"`
+	View          string                                     `json:"view,omitempty"`
+	WidgetsPerRow int32                                      `json:"widgetsPerRow,omitempty"`
+}
+
+type CWDashboardBodyWidgetPropertyAlarm struct {
+	Alarms []string `json:"alarms"`
+	SortBy string   `json:"sortBy,omitempty"`
+	States []string `json:"states,omitempty"`
+	Title  string   `json:"title,omitempty"`
+}
+
+// CWDashboardBodyWidgetPropertyAlarmGraph is the properties of a metric widget that graphs an alarm.
+// CloudWatch renders the metric and threshold of the alarm, so it has no metrics
+type CWDashboardBodyWidgetPropertyAlarmGraph struct {
+	Annotations *CWDashboardBodyWidgetPropertyMetricAnnotations `json:"annotations,omitempty"`
+	Region      string                                          `json:"region"`
+	Title       string                                          `json:"title,omitempty"`
+	View        string                                          `json:"view,omitempty"`
+	YAxis       *CWDashboardBodyWidgetPropertyMetricYAxis       `json:"yAxis,omitempty"`
+}
+
+type CWDashboardBodyWidgetPropertyCustom struct {
+	Endpoint string                                       `json:"endpoint"`
+	Params   json.RawMessage                              `json:"params,omitempty"`
+	Title    string                                       `json:"title,omitempty"`
+	UpdateOn *CWDashboardBodyWidgetPropertyCustomUpdateOn `json:"updateOn,omitempty"`
+}
+
+type CWDashboardBodyWidgetPropertyCustomUpdateOn struct {
+	Refresh   *bool `json:"refresh,omitempty"`
+	Resize    *bool `json:"resize,omitempty"`
+	TimeRange *bool `json:"timeRange,omitempty"`
+}
 
 func buildDashboardBodyJson(ctx context.Context, state dashboardDataSourceModel, rawWidgets []interface{}) (string, error) {
 	widgets := make([]CWDashboardBodyWidget, 0)
-	var currentPosition *widgetPosition
-	for _, rawWidget := range rawWidgets {
-		switch w := rawWidget.(type) {
-		case textWidgetDataSourceSettings:
-			widget, err := w.ToCWDashboardBodyWidget(ctx, w, currentPosition)
-			if err != nil {
-				return "", fmt.Errorf("failed to parse text widget: %w", err)
-			}
-			currentPosition = &widgetPosition{X: widget.X + widget.Width, Y: widget.Y}
-			widgets = append(widgets, widget)
-		case graphWidgetDataSourceSettings:
-			widget, err := w.ToCWDashboardBodyWidget(ctx, currentPosition)
-			if err != nil {
-				return "", fmt.Errorf("failed to parse graph widget: %w", err)
+	enabledRawWidgets := make([]interface{}, 0, len(rawWidgets))
+	for i, rawWidget := range rawWidgets {
+		rawWidget, ok := enabledWidget(rawWidget)
+		if !ok {
+			continue
+		}
+		enabledRawWidgets = append(enabledRawWidgets, rawWidget)
+
+		widget, err := buildWidget(ctx, rawWidget)
+		if err != nil {
+			return "", &widgetError{Index: i, Err: err}
+		}
+		if err := validateWidgetBounds(widget); err != nil {
+			return "", &widgetError{Index: i, Err: err}
+		}
+		widget.Index = i
+		widgets = append(widgets, widget)
+	}
+
+	columns := int32(dashboardDefaultColumns)
+	if !state.Columns.IsNull() {
+		columns = state.Columns.ValueInt32()
+	}
+	if err := fillWidgetWidths(widgets, columns); err != nil {
+		return "", fmt.Errorf("failed to size widgets: %w", err)
+	}
+
+	if state.TableOfContents != nil {
+		toc, err := buildTableOfContents(*state.TableOfContents, enabledRawWidgets)
+		if err != nil {
+			return "", err
+		}
+
+		// the pinned widgets are checked before they are moved down, so that an error shows the position in the settings
+		if err := pinWidgets(newWidgetLayout(MAX_WIDTH), widgets); err != nil {
+			return "", fmt.Errorf("failed to lay out widgets: %w", err)
+		}
+
+		// the table of contents is pinned at the top, so the pinned widgets are moved down below it
+		for i := range widgets {
+			if widgets[i].Pinned {
+				widgets[i].Y += toc.Height
 			}
-			currentPosition = &widgetPosition{X: widget.X + widget.Width, Y: widget.Y}
-			widgets = append(widgets, widget)
-		default:
-			return "", fmt.Errorf("unsupported widget type")
 		}
+		widgets = append([]CWDashboardBodyWidget{toc}, widgets...)
+	}
+
+	if err := layoutWidgets(widgets, state.LayoutMode.ValueString()); err != nil {
+		return "", fmt.Errorf("failed to lay out widgets: %w", err)
+	}
+	widgets = removeSpacers(flattenWidgets(widgets))
+
+	variables := buildDashboardBodyVariables(state.Variables)
+	if err := validateVariablesUsage(widgets, variables); err != nil {
+		return "", err
 	}
 
 	body := CWDashboardBody{
 		Widgets:        widgets,
+		Variables:      variables,
 		Start:          state.Start.ValueString(),
 		End:            state.End.ValueString(),
 		PeriodOverride: state.PeriodOverride.ValueString(),
@@ -187,3 +308,307 @@ func buildDashboardBodyJson(ctx context.Context, state dashboardDataSourceModel,
 
 	return string(bodyBytes), nil
 }
+
+// buildWidget converts the settings of a widget data source into a dashboard widget.
+func buildWidget(ctx context.Context, rawWidget interface{}) (CWDashboardBodyWidget, error) {
+	switch w := rawWidget.(type) {
+	case textWidgetDataSourceSettings:
+		widget, err := w.ToCWDashboardBodyWidget(ctx, w)
+		if err != nil {
+			return CWDashboardBodyWidget{}, fmt.Errorf("failed to parse text widget: %w", err)
+		}
+		return widget, nil
+	case graphWidgetDataSourceSettings:
+		widget, err := w.ToCWDashboardBodyWidget(ctx)
+		if err != nil {
+			return CWDashboardBodyWidget{}, fmt.Errorf("failed to parse graph widget: %w", err)
+		}
+		return widget, nil
+	case logWidgetDataSourceSettings:
+		widget, err := w.ToCWDashboardBodyWidget(ctx)
+		if err != nil {
+			return CWDashboardBodyWidget{}, fmt.Errorf("failed to parse log widget: %w", err)
+		}
+		return widget, nil
+	case alarmStatusWidgetDataSourceSettings:
+		widget, err := w.ToCWDashboardBodyWidget(ctx)
+		if err != nil {
+			return CWDashboardBodyWidget{}, fmt.Errorf("failed to parse alarm status widget: %w", err)
+		}
+		return widget, nil
+	case explorerWidgetDataSourceSettings:
+		widget, err := w.ToCWDashboardBodyWidget(ctx)
+		if err != nil {
+			return CWDashboardBodyWidget{}, fmt.Errorf("failed to parse explorer widget: %w", err)
+		}
+		return widget, nil
+	case alarmWidgetDataSourceSettings:
+		widget, err := w.ToCWDashboardBodyWidget(ctx)
+		if err != nil {
+			return CWDashboardBodyWidget{}, fmt.Errorf("failed to parse alarm widget: %w", err)
+		}
+		return widget, nil
+	case singleValueWidgetDataSourceSettings:
+		widget, err := w.ToCWDashboardBodyWidget(ctx)
+		if err != nil {
+			return CWDashboardBodyWidget{}, fmt.Errorf("failed to parse single value widget: %w", err)
+		}
+		return widget, nil
+	case customWidgetDataSourceSettings:
+		widget, err := w.ToCWDashboardBodyWidget(ctx)
+		if err != nil {
+			return CWDashboardBodyWidget{}, fmt.Errorf("failed to parse custom widget: %w", err)
+		}
+		return widget, nil
+	case rowDataSourceSettings:
+		widget, err := w.ToCWDashboardBodyWidget(ctx)
+		if err != nil {
+			return CWDashboardBodyWidget{}, fmt.Errorf("failed to parse row: %w", err)
+		}
+		return widget, nil
+	case columnDataSourceSettings:
+		widget, err := w.ToCWDashboardBodyWidget(ctx)
+		if err != nil {
+			return CWDashboardBodyWidget{}, fmt.Errorf("failed to parse column: %w", err)
+		}
+		return widget, nil
+	case sectionDataSourceSettings:
+		widget, err := w.ToCWDashboardBodyWidget(ctx)
+		if err != nil {
+			return CWDashboardBodyWidget{}, fmt.Errorf("failed to parse section: %w", err)
+		}
+		return widget, nil
+	case spacerDataSourceSettings:
+		widget, err := w.ToCWDashboardBodyWidget(ctx)
+		if err != nil {
+			return CWDashboardBodyWidget{}, fmt.Errorf("failed to parse spacer: %w", err)
+		}
+		return widget, nil
+	case layoutTemplateWidgetSettings:
+		widget, err := w.ToCWDashboardBodyWidget(ctx)
+		if err != nil {
+			return CWDashboardBodyWidget{}, fmt.Errorf("failed to parse widget placed by layout: %w", err)
+		}
+		return widget, nil
+	default:
+		return CWDashboardBodyWidget{}, fmt.Errorf("unsupported widget type")
+	}
+}
+
+// enabledWidget returns the settings of a widget without its disabled widgets, if it is a row, a column or a section.
+// It returns false when the widget is disabled, or when no widget is left in the group.
+func enabledWidget(rawWidget interface{}) (interface{}, bool) {
+	switch w := rawWidget.(type) {
+	case textWidgetDataSourceSettings:
+		return w, widgetEnabled(w.Enabled)
+	case graphWidgetDataSourceSettings:
+		return w, widgetEnabled(w.Enabled)
+	case logWidgetDataSourceSettings:
+		return w, widgetEnabled(w.Enabled)
+	case alarmStatusWidgetDataSourceSettings:
+		return w, widgetEnabled(w.Enabled)
+	case explorerWidgetDataSourceSettings:
+		return w, widgetEnabled(w.Enabled)
+	case alarmWidgetDataSourceSettings:
+		return w, widgetEnabled(w.Enabled)
+	case singleValueWidgetDataSourceSettings:
+		return w, widgetEnabled(w.Enabled)
+	case customWidgetDataSourceSettings:
+		return w, widgetEnabled(w.Enabled)
+	case spacerDataSourceSettings:
+		return w, widgetEnabled(w.Enabled)
+	case rowDataSourceSettings:
+		w.Children = enabledWidgets(w.Children)
+		return w, widgetEnabled(w.Enabled) && len(w.Children) > 0
+	case columnDataSourceSettings:
+		w.Children = enabledWidgets(w.Children)
+		return w, widgetEnabled(w.Enabled) && len(w.Children) > 0
+	case sectionDataSourceSettings:
+		w.Children = enabledWidgets(w.Children)
+		return w, widgetEnabled(w.Enabled) && len(w.Children) > 0
+	case layoutTemplateWidgetSettings:
+		widget, ok := enabledWidget(w.Widget)
+		w.Widget = widget
+		return w, ok
+	default:
+		return rawWidget, true
+	}
+}
+
+func enabledWidgets(rawWidgets []interface{}) []interface{} {
+	enabled := make([]interface{}, 0, len(rawWidgets))
+	for _, rawWidget := range rawWidgets {
+		if w, ok := enabledWidget(rawWidget); ok {
+			enabled = append(enabled, w)
+		}
+	}
+
+	return enabled
+}
+
+// buildTableOfContents builds a full-width text widget pinned at the top that lists the titles of the sections, nesting the sections in other sections.
+func buildTableOfContents(model dashboardTableOfContentsDataSourceModel, rawWidgets []interface{}) (CWDashboardBodyWidget, error) {
+	entries := collectSectionTitles(rawWidgets, 0)
+	if len(entries) == 0 {
+		return CWDashboardBodyWidget{}, fmt.Errorf("table_of_contents requires at least one section")
+	}
+
+	title := dashboardTableOfContentsDefaultTitle
+	if !model.Title.IsNull() {
+		title = model.Title.ValueString()
+	}
+
+	height := int32(len(entries) + 1)
+	if !model.Height.IsNull() {
+		height = model.Height.ValueInt32()
+	}
+
+	return CWDashboardBodyWidget{
+		Type:   typeTextWidget,
+		X:      0,
+		Y:      0,
+		Width:  MAX_WIDTH,
+		Height: height,
+		Pinned: true,
+		Properties: CWDashboardBodyWidgetPropertyText{
+			Markdown: "## " + title + "\n\n" + strings.Join(entries, "\n"),
+		},
+	}, nil
+}
+
+// collectSectionTitles returns a markdown list item for every section in the widgets, in the order they appear.
+func collectSectionTitles(rawWidgets []interface{}, depth int) []string {
+	entries := make([]string, 0)
+	for _, rawWidget := range rawWidgets {
+		switch w := rawWidget.(type) {
+		case sectionDataSourceSettings:
+			entries = append(entries, strings.Repeat("  ", depth)+"- "+w.Title)
+			entries = append(entries, collectSectionTitles(w.Children, depth+1)...)
+		case rowDataSourceSettings:
+			entries = append(entries, collectSectionTitles(w.Children, depth)...)
+		case columnDataSourceSettings:
+			entries = append(entries, collectSectionTitles(w.Children, depth)...)
+		case layoutTemplateWidgetSettings:
+			entries = append(entries, collectSectionTitles([]interface{}{w.Widget}, depth)...)
+		}
+	}
+
+	return entries
+}
+
+func buildDashboardBodyVariables(models []dashboardVariableDataSourceModel) []CWDashboardBodyVariable {
+	variables := make([]CWDashboardBodyVariable, 0, len(models))
+	for _, m := range models {
+		variable := CWDashboardBodyVariable{
+			Type:         m.Type.ValueString(),
+			Property:     m.Property.ValueString(),
+			Pattern:      m.Pattern.ValueString(),
+			InputType:    m.InputType.ValueString(),
+			Id:           m.Id.ValueString(),
+			Label:        m.Label.ValueString(),
+			DefaultValue: m.DefaultValue.ValueString(),
+			// NOTE: variables are visible unless explicitly hidden
+			Visible:      m.Visible.IsNull() || m.Visible.ValueBool(),
+			Search:       m.Search.ValueString(),
+			PopulateFrom: m.PopulateFrom.ValueString(),
+		}
+		for _, v := range m.Values {
+			variable.Values = append(variable.Values, CWDashboardBodyVariableValue{
+				Value: v.Value.ValueString(),
+				Label: v.Label.ValueString(),
+			})
+		}
+		variables = append(variables, variable)
+	}
+
+	return variables
+}
+
+// validateVariablesUsage checks that every variable changes something on the dashboard.
+// A property variable must match a dimension name of a metric or the region of a widget,
+// and a pattern variable must appear in a value it can replace: a dimension value, a metric expression, a region, an account or a log query.
+// Titles, labels and markdown are not replaced by variables, so they are not searched.
+func validateVariablesUsage(widgets []CWDashboardBodyWidget, variables []CWDashboardBodyVariable) error {
+	if len(variables) == 0 {
+		return nil
+	}
+
+	properties, values, err := collectVariableTargets(widgets)
+	if err != nil {
+		return err
+	}
+
+	for _, v := range variables {
+		switch v.Type {
+		case dashboardVariableTypeProperty:
+			if !slices.Contains(properties, v.Property) {
+				return fmt.Errorf("variable %q is not used by any widget: property %q not found", v.Id, v.Property)
+			}
+		case dashboardVariableTypePattern:
+			if !slices.ContainsFunc(values, func(value string) bool { return strings.Contains(value, v.Pattern) }) {
+				return fmt.Errorf("variable %q is not used by any widget: pattern %q not found", v.Id, v.Pattern)
+			}
+		}
+	}
+
+	return nil
+}
+
+// collectVariableTargets returns the properties a property variable can change, and the values a pattern variable can replace in the widgets.
+func collectVariableTargets(widgets []CWDashboardBodyWidget) ([]string, []string, error) {
+	var properties, values []string
+	for _, w := range widgets {
+		b, err := json.Marshal(w.Properties)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to marshal widget properties: %w", err)
+		}
+		var widgetProperties map[string]interface{}
+		if err := json.Unmarshal(b, &widgetProperties); err != nil {
+			// the properties of the widget are not an object, so there is nothing to change
+			continue
+		}
+
+		if region, ok := widgetProperties["region"].(string); ok {
+			properties = append(properties, "region")
+			values = append(values, region)
+		}
+		for _, key := range []string{"accountId", "query"} {
+			if value, ok := widgetProperties[key].(string); ok {
+				values = append(values, value)
+			}
+		}
+
+		metrics, _ := widgetProperties["metrics"].([]interface{})
+		for _, metric := range metrics {
+			metricSettings, ok := metric.([]interface{})
+			if !ok {
+				continue
+			}
+
+			// [namespace, metricName, dimension name, dimension value, ..., rendering properties]
+			for k := 2; k+1 < len(metricSettings); k += 2 {
+				name, nameOk := metricSettings[k].(string)
+				value, valueOk := metricSettings[k+1].(string)
+				if nameOk && valueOk {
+					properties = append(properties, name)
+					values = append(values, value)
+				}
+			}
+
+			if len(metricSettings) == 0 {
+				continue
+			}
+			renderingProperties, ok := metricSettings[len(metricSettings)-1].(map[string]interface{})
+			if !ok {
+				continue
+			}
+			for _, key := range []string{"expression", "region", "accountId"} {
+				if value, ok := renderingProperties[key].(string); ok {
+					values = append(values, value)
+				}
+			}
+		}
+	}
+
+	return properties, values, nil
+}
@@ -0,0 +1,662 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"github.com/tj/assert"
+)
+
+func TestBuildDashboardBodyJson_Variables(t *testing.T) {
+	widgets := []interface{}{
+		graphWidgetDataSourceSettings{
+			Width:  24,
+			Height: 6,
+			Title:  "CPU of i-0123456789abcdef0",
+			Left: []IMetricSettings{
+				&metricDataSourceSettings{
+					MetricName:    "CPUUtilization",
+					Namespace:     "AWS/EC2",
+					DimensionsMap: map[string]string{"InstanceId": "i-0123456789abcdef0"},
+				},
+			},
+		},
+	}
+
+	t.Run("should render variables used by widgets", func(t *testing.T) {
+		state := dashboardDataSourceModel{
+			Variables: []dashboardVariableDataSourceModel{
+				{
+					Type:      types.StringValue("property"),
+					Property:  types.StringValue("InstanceId"),
+					InputType: types.StringValue("input"),
+					Id:        types.StringValue("instance"),
+					Visible:   types.BoolValue(false),
+				},
+				{
+					Type:         types.StringValue("pattern"),
+					Pattern:      types.StringValue("i-0123456789abcdef0"),
+					InputType:    types.StringValue("select"),
+					Id:           types.StringValue("title"),
+					Search:       types.StringValue(`{AWS/EC2,InstanceId} MetricName="CPUUtilization"`),
+					PopulateFrom: types.StringValue("InstanceId"),
+				},
+			},
+		}
+
+		actual, err := buildDashboardBodyJson(context.Background(), state, widgets)
+		require.NoError(t, err)
+
+		assert.Contains(t, actual, `"variables":[{"type":"property","property":"InstanceId","inputType":"input","id":"instance","visible":false},`+
+			`{"type":"pattern","pattern":"i-0123456789abcdef0","inputType":"select","id":"title","visible":true,"search":"{AWS/EC2,InstanceId} MetricName=\"CPUUtilization\"","populateFrom":"InstanceId"}]`)
+	})
+
+	t.Run("should fail when a property variable is not used", func(t *testing.T) {
+		state := dashboardDataSourceModel{
+			Variables: []dashboardVariableDataSourceModel{
+				{
+					Type:      types.StringValue("property"),
+					Property:  types.StringValue("AutoScalingGroupName"),
+					InputType: types.StringValue("input"),
+					Id:        types.StringValue("asg"),
+				},
+			},
+		}
+
+		_, err := buildDashboardBodyJson(context.Background(), state, widgets)
+		assert.EqualError(t, err, `variable "asg" is not used by any widget: property "AutoScalingGroupName" not found`)
+	})
+
+	t.Run("should fail when a pattern variable is not used", func(t *testing.T) {
+		state := dashboardDataSourceModel{
+			Variables: []dashboardVariableDataSourceModel{
+				{
+					Type:      types.StringValue("pattern"),
+					Pattern:   types.StringValue("i-unknown"),
+					InputType: types.StringValue("input"),
+					Id:        types.StringValue("instance"),
+				},
+			},
+		}
+
+		_, err := buildDashboardBodyJson(context.Background(), state, widgets)
+		assert.EqualError(t, err, `variable "instance" is not used by any widget: pattern "i-unknown" not found`)
+	})
+
+	t.Run("should fail when a pattern variable is only in a title or a label", func(t *testing.T) {
+		labeled := []interface{}{
+			textWidgetDataSourceSettings{Markdown: "# i-0fedcba9876543210"},
+			graphWidgetDataSourceSettings{
+				Title: "CPU of i-0fedcba9876543210",
+				Left: []IMetricSettings{
+					&metricDataSourceSettings{
+						MetricName:    "CPUUtilization",
+						Namespace:     "AWS/EC2",
+						DimensionsMap: map[string]string{"InstanceId": "i-0123456789abcdef0"},
+						Label:         "i-0fedcba9876543210",
+					},
+				},
+			},
+		}
+		state := dashboardDataSourceModel{
+			Variables: []dashboardVariableDataSourceModel{
+				{
+					Type:      types.StringValue("pattern"),
+					Pattern:   types.StringValue("i-0fedcba9876543210"),
+					InputType: types.StringValue("input"),
+					Id:        types.StringValue("instance"),
+				},
+			},
+		}
+
+		_, err := buildDashboardBodyJson(context.Background(), state, labeled)
+		assert.EqualError(t, err, `variable "instance" is not used by any widget: pattern "i-0fedcba9876543210" not found`)
+	})
+
+	t.Run("should fail when a property variable is only in a markdown", func(t *testing.T) {
+		state := dashboardDataSourceModel{
+			Variables: []dashboardVariableDataSourceModel{
+				{
+					Type:      types.StringValue("property"),
+					Property:  types.StringValue("InstanceType"),
+					InputType: types.StringValue("input"),
+					Id:        types.StringValue("type"),
+				},
+			},
+		}
+
+		_, err := buildDashboardBodyJson(context.Background(), state, append([]interface{}{
+			textWidgetDataSourceSettings{Markdown: "InstanceType"},
+		}, widgets...))
+		assert.EqualError(t, err, `variable "type" is not used by any widget: property "InstanceType" not found`)
+	})
+
+	t.Run("should accept a property variable changing the region", func(t *testing.T) {
+		state := dashboardDataSourceModel{
+			Variables: []dashboardVariableDataSourceModel{
+				{
+					Type:      types.StringValue("property"),
+					Property:  types.StringValue("region"),
+					InputType: types.StringValue("select"),
+					Id:        types.StringValue("region"),
+				},
+			},
+		}
+
+		_, err := buildDashboardBodyJson(context.Background(), state, widgets)
+		assert.NoError(t, err)
+	})
+}
+
+func TestBuildDashboardBodyJson_Layout(t *testing.T) {
+	widgets := []interface{}{
+		textWidgetDataSourceSettings{Width: 8, Height: 2, Markdown: "# Overview"},
+		graphWidgetDataSourceSettings{Width: 8, Height: 6},
+		logWidgetDataSourceSettings{Width: 8, Height: 4, LogGroupNames: []string{"/aws/lambda/a"}, Query: "limit 20"},
+		textWidgetDataSourceSettings{Width: 12, Height: 2, Markdown: "# Details"},
+		graphWidgetDataSourceSettings{Width: 12, Height: 6},
+	}
+
+	actual, err := buildDashboardBodyJson(context.Background(), dashboardDataSourceModel{}, widgets)
+	require.NoError(t, err)
+
+	var body CWDashboardBody
+	require.NoError(t, json.Unmarshal([]byte(actual), &body))
+
+	positions := make([]widgetPosition, 0, len(body.Widgets))
+	for _, w := range body.Widgets {
+		positions = append(positions, widgetPosition{X: w.X, Y: w.Y})
+	}
+
+	// the second row starts below the graph, the tallest widget of the first row
+	assert.Equal(t, []widgetPosition{
+		{X: 0, Y: 0},
+		{X: 8, Y: 0},
+		{X: 16, Y: 0},
+		{X: 0, Y: 6},
+		{X: 12, Y: 6},
+	}, positions)
+}
+
+func TestBuildDashboardBodyJson_PinnedWidgets(t *testing.T) {
+	t.Run("should flow widgets around a pinned widget", func(t *testing.T) {
+		widgets := []interface{}{
+			textWidgetDataSourceSettings{Width: 6, Height: 2, Markdown: "legend", X: ptr(int32(18)), Y: ptr(int32(0))},
+			graphWidgetDataSourceSettings{Width: 12, Height: 6},
+			graphWidgetDataSourceSettings{Width: 12, Height: 6},
+		}
+
+		actual, err := buildDashboardBodyJson(context.Background(), dashboardDataSourceModel{}, widgets)
+		require.NoError(t, err)
+
+		var body CWDashboardBody
+		require.NoError(t, json.Unmarshal([]byte(actual), &body))
+
+		positions := make([]widgetPosition, 0, len(body.Widgets))
+		for _, w := range body.Widgets {
+			positions = append(positions, widgetPosition{X: w.X, Y: w.Y})
+		}
+		assert.Equal(t, []widgetPosition{
+			{X: 18, Y: 0},
+			{X: 0, Y: 0},
+			{X: 0, Y: 6},
+		}, positions)
+	})
+
+	t.Run("should fail when pinned widgets overlap", func(t *testing.T) {
+		widgets := []interface{}{
+			textWidgetDataSourceSettings{Width: 6, Height: 2, Markdown: "a", X: ptr(int32(0)), Y: ptr(int32(0))},
+			logWidgetDataSourceSettings{Width: 12, Height: 6, LogGroupNames: []string{"/aws/lambda/a"}, Query: "limit 20", X: ptr(int32(4)), Y: ptr(int32(1))},
+		}
+
+		_, err := buildDashboardBodyJson(context.Background(), dashboardDataSourceModel{}, widgets)
+		assert.EqualError(t, err, "failed to lay out widgets: invalid widget at index 1: invalid position: widget at x=4, y=1 overlaps the widget at x=0, y=0")
+	})
+}
+
+func TestBuildDashboardBodyJson_Groups(t *testing.T) {
+	widgets := []interface{}{
+		textWidgetDataSourceSettings{Width: 24, Height: 1, Markdown: "# Service"},
+		rowDataSourceSettings{
+			Children: []interface{}{
+				columnDataSourceSettings{
+					Children: []interface{}{
+						textWidgetDataSourceSettings{Width: 8, Height: 2, Markdown: "status"},
+						graphWidgetDataSourceSettings{Width: 8, Height: 4},
+					},
+				},
+				graphWidgetDataSourceSettings{Width: 8, Height: 6},
+				graphWidgetDataSourceSettings{Width: 8, Height: 3},
+			},
+		},
+		graphWidgetDataSourceSettings{Width: 12, Height: 6},
+	}
+
+	actual, err := buildDashboardBodyJson(context.Background(), dashboardDataSourceModel{}, widgets)
+	require.NoError(t, err)
+
+	var body CWDashboardBody
+	require.NoError(t, json.Unmarshal([]byte(actual), &body))
+
+	rects := make([]widgetRect, 0, len(body.Widgets))
+	for _, w := range body.Widgets {
+		rects = append(rects, widgetRect{widgetPosition{X: w.X, Y: w.Y}, widgetSize{Width: w.Width, Height: w.Height}})
+	}
+
+	// rows and columns are replaced with their widgets
+	assert.Equal(t, []widgetRect{
+		{widgetPosition{X: 0, Y: 0}, widgetSize{Width: 24, Height: 1}},
+		{widgetPosition{X: 0, Y: 1}, widgetSize{Width: 8, Height: 2}},
+		{widgetPosition{X: 0, Y: 3}, widgetSize{Width: 8, Height: 4}},
+		{widgetPosition{X: 8, Y: 1}, widgetSize{Width: 8, Height: 6}},
+		{widgetPosition{X: 16, Y: 1}, widgetSize{Width: 8, Height: 3}},
+		{widgetPosition{X: 0, Y: 7}, widgetSize{Width: 12, Height: 6}},
+	}, rects)
+	for _, w := range body.Widgets {
+		assert.NotEqual(t, widgetGroupTypeRow, w.Type)
+		assert.NotEqual(t, widgetGroupTypeColumn, w.Type)
+	}
+}
+
+func TestBuildDashboardBodyJson_TableOfContents(t *testing.T) {
+	widgets := []interface{}{
+		sectionDataSourceSettings{
+			Title: "API",
+			Children: []interface{}{
+				graphWidgetDataSourceSettings{Width: 12, Height: 6},
+				sectionDataSourceSettings{
+					Title: "Errors",
+					Children: []interface{}{
+						graphWidgetDataSourceSettings{Width: 12, Height: 6},
+					},
+				},
+			},
+		},
+		rowDataSourceSettings{
+			Children: []interface{}{
+				sectionDataSourceSettings{
+					Title: "Database",
+					Children: []interface{}{
+						graphWidgetDataSourceSettings{Width: 12, Height: 6},
+					},
+				},
+			},
+		},
+	}
+
+	t.Run("should list every section at the top", func(t *testing.T) {
+		state := dashboardDataSourceModel{
+			TableOfContents: &dashboardTableOfContentsDataSourceModel{},
+		}
+
+		actual, err := buildDashboardBodyJson(context.Background(), state, widgets)
+		require.NoError(t, err)
+
+		var body CWDashboardBody
+		require.NoError(t, json.Unmarshal([]byte(actual), &body))
+
+		toc := body.Widgets[0]
+		assert.Equal(t, typeTextWidget, toc.Type)
+		assert.Equal(t, widgetRect{widgetPosition{X: 0, Y: 0}, widgetSize{Width: 24, Height: 4}}, widgetRect{widgetPosition{X: toc.X, Y: toc.Y}, widgetSize{Width: toc.Width, Height: toc.Height}})
+		assert.Equal(t, map[string]interface{}{"markdown": "## Contents\n\n- API\n  - Errors\n- Database"}, toc.Properties)
+
+		// the header of the first section is placed below the table of contents
+		assert.Equal(t, map[string]interface{}{"markdown": "## API"}, body.Widgets[1].Properties)
+		assert.Equal(t, int32(4), body.Widgets[1].Y)
+	})
+
+	t.Run("should use the given title and height", func(t *testing.T) {
+		state := dashboardDataSourceModel{
+			TableOfContents: &dashboardTableOfContentsDataSourceModel{
+				Title:  types.StringValue("Index"),
+				Height: types.Int32Value(2),
+			},
+		}
+
+		actual, err := buildDashboardBodyJson(context.Background(), state, widgets)
+		require.NoError(t, err)
+
+		var body CWDashboardBody
+		require.NoError(t, json.Unmarshal([]byte(actual), &body))
+
+		assert.Equal(t, int32(2), body.Widgets[0].Height)
+		assert.Equal(t, map[string]interface{}{"markdown": "## Index\n\n- API\n  - Errors\n- Database"}, body.Widgets[0].Properties)
+	})
+
+	t.Run("should move the pinned widgets below the table of contents", func(t *testing.T) {
+		state := dashboardDataSourceModel{
+			TableOfContents: &dashboardTableOfContentsDataSourceModel{},
+		}
+
+		actual, err := buildDashboardBodyJson(context.Background(), state, []interface{}{
+			graphWidgetDataSourceSettings{Width: 12, Height: 6, X: ptr(int32(12)), Y: ptr(int32(0))},
+			sectionDataSourceSettings{
+				Title: "API",
+				Children: []interface{}{
+					graphWidgetDataSourceSettings{Width: 12, Height: 6},
+				},
+			},
+		})
+		require.NoError(t, err)
+
+		var body CWDashboardBody
+		require.NoError(t, json.Unmarshal([]byte(actual), &body))
+
+		positions := make([]widgetPosition, 0, len(body.Widgets))
+		for _, w := range body.Widgets {
+			positions = append(positions, widgetPosition{X: w.X, Y: w.Y})
+		}
+		assert.Equal(t, []widgetPosition{
+			{X: 0, Y: 0},
+			{X: 12, Y: 2},
+			{X: 0, Y: 8},
+			{X: 0, Y: 9},
+		}, positions)
+	})
+
+	t.Run("should show the position in the settings when pinned widgets overlap", func(t *testing.T) {
+		state := dashboardDataSourceModel{
+			TableOfContents: &dashboardTableOfContentsDataSourceModel{},
+		}
+
+		_, err := buildDashboardBodyJson(context.Background(), state, []interface{}{
+			sectionDataSourceSettings{
+				Title: "API",
+				Children: []interface{}{
+					graphWidgetDataSourceSettings{Width: 12, Height: 6},
+				},
+			},
+			textWidgetDataSourceSettings{Width: 6, Height: 2, Markdown: "a", X: ptr(int32(0)), Y: ptr(int32(0))},
+			textWidgetDataSourceSettings{Width: 6, Height: 2, Markdown: "b", X: ptr(int32(4)), Y: ptr(int32(1))},
+		})
+		assert.EqualError(t, err, "failed to lay out widgets: invalid widget at index 2: invalid position: widget at x=4, y=1 overlaps the widget at x=0, y=0")
+
+		var we *widgetError
+		require.ErrorAs(t, err, &we)
+		assert.Equal(t, 2, we.Index)
+	})
+
+	t.Run("should fail without sections", func(t *testing.T) {
+		state := dashboardDataSourceModel{
+			TableOfContents: &dashboardTableOfContentsDataSourceModel{},
+		}
+
+		_, err := buildDashboardBodyJson(context.Background(), state, []interface{}{
+			graphWidgetDataSourceSettings{Width: 12, Height: 6},
+		})
+		assert.EqualError(t, err, "table_of_contents requires at least one section")
+	})
+}
+
+func TestBuildDashboardBodyJson_Columns(t *testing.T) {
+	widgets := []interface{}{
+		textWidgetDataSourceSettings{Markdown: "# Service"},
+		graphWidgetDataSourceSettings{},
+		graphWidgetDataSourceSettings{Width: 12},
+		singleValueWidgetDataSourceSettings{},
+		graphWidgetDataSourceSettings{},
+	}
+
+	state := dashboardDataSourceModel{
+		Columns: types.Int32Value(2),
+	}
+
+	actual, err := buildDashboardBodyJson(context.Background(), state, widgets)
+	require.NoError(t, err)
+
+	var body CWDashboardBody
+	require.NoError(t, json.Unmarshal([]byte(actual), &body))
+
+	rects := make([]widgetRect, 0, len(body.Widgets))
+	for _, w := range body.Widgets {
+		rects = append(rects, widgetRect{widgetPosition{X: w.X, Y: w.Y}, widgetSize{Width: w.Width, Height: w.Height}})
+	}
+	assert.Equal(t, []widgetRect{
+		{widgetPosition{X: 0, Y: 0}, widgetSize{Width: 12, Height: 2}},
+		{widgetPosition{X: 12, Y: 0}, widgetSize{Width: 12, Height: 6}},
+		{widgetPosition{X: 0, Y: 6}, widgetSize{Width: 12, Height: 6}},
+		{widgetPosition{X: 12, Y: 6}, widgetSize{Width: 12, Height: 3}},
+		{widgetPosition{X: 0, Y: 12}, widgetSize{Width: 24, Height: 6}},
+	}, rects)
+}
+
+func TestBuildDashboardBodyJson_ColumnsWithHeaders(t *testing.T) {
+	widgets := []interface{}{
+		textWidgetDataSourceSettings{Width: 24, Height: 1, Markdown: "# Service"},
+		graphWidgetDataSourceSettings{},
+		graphWidgetDataSourceSettings{},
+		graphWidgetDataSourceSettings{},
+		sectionDataSourceSettings{
+			Title: "Details",
+			Children: []interface{}{
+				graphWidgetDataSourceSettings{Width: 12},
+			},
+		},
+		graphWidgetDataSourceSettings{},
+		graphWidgetDataSourceSettings{},
+	}
+
+	state := dashboardDataSourceModel{
+		Columns:         types.Int32Value(3),
+		TableOfContents: &dashboardTableOfContentsDataSourceModel{},
+	}
+
+	actual, err := buildDashboardBodyJson(context.Background(), state, widgets)
+	require.NoError(t, err)
+
+	var body CWDashboardBody
+	require.NoError(t, json.Unmarshal([]byte(actual), &body))
+
+	rects := make([]widgetRect, 0, len(body.Widgets))
+	for _, w := range body.Widgets {
+		rects = append(rects, widgetRect{widgetPosition{X: w.X, Y: w.Y}, widgetSize{Width: w.Width, Height: w.Height}})
+	}
+	// the table of contents, the header and the section fill their rows, so the graphs around them share the rows by 3
+	assert.Equal(t, []widgetRect{
+		{widgetPosition{X: 0, Y: 0}, widgetSize{Width: 24, Height: 2}},
+		{widgetPosition{X: 0, Y: 2}, widgetSize{Width: 24, Height: 1}},
+		{widgetPosition{X: 0, Y: 3}, widgetSize{Width: 8, Height: 6}},
+		{widgetPosition{X: 8, Y: 3}, widgetSize{Width: 8, Height: 6}},
+		{widgetPosition{X: 16, Y: 3}, widgetSize{Width: 8, Height: 6}},
+		{widgetPosition{X: 0, Y: 9}, widgetSize{Width: 24, Height: 1}},
+		{widgetPosition{X: 0, Y: 10}, widgetSize{Width: 12, Height: 6}},
+		{widgetPosition{X: 0, Y: 16}, widgetSize{Width: 12, Height: 6}},
+		{widgetPosition{X: 12, Y: 16}, widgetSize{Width: 12, Height: 6}},
+	}, rects)
+}
+
+func TestBuildDashboardBodyJson_WidgetBounds(t *testing.T) {
+	t.Run("should point at the index of a widget wider than the dashboard", func(t *testing.T) {
+		widgets := []interface{}{
+			graphWidgetDataSourceSettings{Width: 12, Height: 6},
+			textWidgetDataSourceSettings{Width: 30, Height: 2, Markdown: "a"},
+		}
+
+		_, err := buildDashboardBodyJson(context.Background(), dashboardDataSourceModel{}, widgets)
+		assert.EqualError(t, err, "invalid widget at index 1: width must be between 1 and 24, got: 30")
+
+		var we *widgetError
+		require.ErrorAs(t, err, &we)
+		assert.Equal(t, 1, we.Index)
+	})
+
+	t.Run("should check the widgets in groups", func(t *testing.T) {
+		widgets := []interface{}{
+			columnDataSourceSettings{
+				Children: []interface{}{
+					graphWidgetDataSourceSettings{Width: 12, Height: -6},
+				},
+			},
+		}
+
+		_, err := buildDashboardBodyJson(context.Background(), dashboardDataSourceModel{}, widgets)
+		assert.EqualError(t, err, "invalid widget at index 0: failed to parse column: invalid widget at index 0 of column: height must be greater than 0, got: -6")
+	})
+
+	t.Run("should point at the index in the settings when a disabled widget is before an overlapping pinned widget", func(t *testing.T) {
+		widgets := []interface{}{
+			graphWidgetDataSourceSettings{Width: 12, Height: 6, Enabled: ptr(false)},
+			textWidgetDataSourceSettings{Width: 6, Height: 2, Markdown: "a", X: ptr(int32(0)), Y: ptr(int32(0))},
+			textWidgetDataSourceSettings{Width: 6, Height: 2, Markdown: "b", X: ptr(int32(4)), Y: ptr(int32(1))},
+		}
+
+		_, err := buildDashboardBodyJson(context.Background(), dashboardDataSourceModel{}, widgets)
+		assert.EqualError(t, err, "failed to lay out widgets: invalid widget at index 2: invalid position: widget at x=4, y=1 overlaps the widget at x=0, y=0")
+
+		var we *widgetError
+		require.ErrorAs(t, err, &we)
+		assert.Equal(t, 2, we.Index)
+	})
+
+}
+
+func TestBuildDashboardBodyJson_Spacers(t *testing.T) {
+	widgets := []interface{}{
+		graphWidgetDataSourceSettings{Width: 8, Height: 6},
+		spacerDataSourceSettings{Width: 8},
+		graphWidgetDataSourceSettings{Width: 8, Height: 6},
+		rowDataSourceSettings{
+			Children: []interface{}{
+				spacerDataSourceSettings{},
+				textWidgetDataSourceSettings{Markdown: "a"},
+			},
+		},
+	}
+
+	actual, err := buildDashboardBodyJson(context.Background(), dashboardDataSourceModel{}, widgets)
+	require.NoError(t, err)
+
+	var body CWDashboardBody
+	require.NoError(t, json.Unmarshal([]byte(actual), &body))
+
+	// spacers hold their cells, but are not in the dashboard
+	rects := make([]widgetRect, 0, len(body.Widgets))
+	for _, w := range body.Widgets {
+		assert.NotEqual(t, widgetTypeSpacer, w.Type)
+		rects = append(rects, widgetRect{widgetPosition{X: w.X, Y: w.Y}, widgetSize{Width: w.Width, Height: w.Height}})
+	}
+	assert.Equal(t, []widgetRect{
+		{widgetPosition{X: 0, Y: 0}, widgetSize{Width: 8, Height: 6}},
+		{widgetPosition{X: 16, Y: 0}, widgetSize{Width: 8, Height: 6}},
+		{widgetPosition{X: 12, Y: 6}, widgetSize{Width: 12, Height: 2}},
+	}, rects)
+}
+
+func TestBuildDashboardBodyJson_Enabled(t *testing.T) {
+	disabled := ptr(false)
+
+	t.Run("should drop disabled widgets and reflow the others", func(t *testing.T) {
+		widgets := []interface{}{
+			graphWidgetDataSourceSettings{Width: 12, Height: 6, Enabled: disabled},
+			graphWidgetDataSourceSettings{Width: 12, Height: 6, Title: "b", Enabled: ptr(true)},
+			rowDataSourceSettings{
+				Children: []interface{}{
+					textWidgetDataSourceSettings{Markdown: "c"},
+					textWidgetDataSourceSettings{Markdown: "d", Enabled: disabled},
+				},
+			},
+			sectionDataSourceSettings{
+				Title: "Production",
+				Children: []interface{}{
+					graphWidgetDataSourceSettings{Width: 12, Height: 6, Enabled: disabled},
+				},
+			},
+			sectionDataSourceSettings{
+				Title:   "Staging",
+				Enabled: disabled,
+				Children: []interface{}{
+					graphWidgetDataSourceSettings{Width: 12, Height: 6},
+				},
+			},
+			sectionDataSourceSettings{
+				Title: "Shared",
+				Children: []interface{}{
+					graphWidgetDataSourceSettings{Width: 12, Height: 6},
+				},
+			},
+		}
+		state := dashboardDataSourceModel{
+			TableOfContents: &dashboardTableOfContentsDataSourceModel{},
+		}
+
+		actual, err := buildDashboardBodyJson(context.Background(), state, widgets)
+		require.NoError(t, err)
+
+		var body CWDashboardBody
+		require.NoError(t, json.Unmarshal([]byte(actual), &body))
+
+		rects := make([]widgetRect, 0, len(body.Widgets))
+		for _, w := range body.Widgets {
+			rects = append(rects, widgetRect{widgetPosition{X: w.X, Y: w.Y}, widgetSize{Width: w.Width, Height: w.Height}})
+		}
+		assert.Equal(t, []widgetRect{
+			{widgetPosition{X: 0, Y: 0}, widgetSize{Width: 24, Height: 2}},
+			{widgetPosition{X: 0, Y: 2}, widgetSize{Width: 12, Height: 6}},
+			{widgetPosition{X: 0, Y: 8}, widgetSize{Width: 24, Height: 2}},
+			{widgetPosition{X: 0, Y: 10}, widgetSize{Width: 24, Height: 1}},
+			{widgetPosition{X: 0, Y: 11}, widgetSize{Width: 12, Height: 6}},
+		}, rects)
+
+		// the sections left without widgets are dropped from the table of contents too
+		assert.Equal(t, map[string]interface{}{"markdown": "## Contents\n\n- Shared"}, body.Widgets[0].Properties)
+	})
+
+	t.Run("should point at the index in widgets after a disabled widget", func(t *testing.T) {
+		widgets := []interface{}{
+			graphWidgetDataSourceSettings{Width: 12, Height: 6, Enabled: disabled},
+			textWidgetDataSourceSettings{Width: 30, Markdown: "a"},
+		}
+
+		_, err := buildDashboardBodyJson(context.Background(), dashboardDataSourceModel{}, widgets)
+		assert.EqualError(t, err, "invalid widget at index 1: width must be between 1 and 24, got: 30")
+	})
+}
+
+func TestBuildDashboardBodyJson_Deterministic(t *testing.T) {
+	dimensions := map[string]string{
+		"LoadBalancer":     "app/app/0123456789abcdef",
+		"TargetGroup":      "targetgroup/app/0123456789abcdef",
+		"AvailabilityZone": "us-east-1a",
+	}
+	widgets := []interface{}{
+		graphWidgetDataSourceSettings{
+			Title: "Requests",
+			Left: []IMetricSettings{
+				&metricDataSourceSettings{
+					MetricName:    "RequestCount",
+					Namespace:     "AWS/ApplicationELB",
+					DimensionsMap: dimensions,
+					Statistic:     "Sum",
+					Account:       "123456789012",
+					Region:        "us-east-1",
+					Unit:          "Count",
+				},
+			},
+			Right: []IMetricSettings{
+				&metricExpressionDataSourceSettings{
+					Expression: "m1 / m2 * 100",
+					UsingMetrics: map[string]string{
+						"m2": `{"type":"metric","metricName":"RequestCount","namespace":"AWS/ApplicationELB","dimensionsMap":{"TargetGroup":"targetgroup/app/0123456789abcdef","LoadBalancer":"app/app/0123456789abcdef"},"statistic":"Sum"}`,
+						"m1": `{"type":"metric","metricName":"HTTPCode_Target_5XX_Count","namespace":"AWS/ApplicationELB","dimensionsMap":{"TargetGroup":"targetgroup/app/0123456789abcdef","LoadBalancer":"app/app/0123456789abcdef"},"statistic":"Sum"}`,
+					},
+					Label: "error rate",
+				},
+			},
+		},
+	}
+
+	expected, err := buildDashboardBodyJson(context.Background(), dashboardDataSourceModel{}, widgets)
+	require.NoError(t, err)
+	assert.Contains(t, expected, `["AWS/ApplicationELB","RequestCount","AvailabilityZone","us-east-1a","LoadBalancer","app/app/0123456789abcdef","TargetGroup","targetgroup/app/0123456789abcdef",`+
+		`{"accountId":"123456789012","region":"us-east-1","stat":"Sum","unit":"Count","yAxis":"left"}]`)
+
+	// the same inputs always give the same json, byte for byte
+	for i := 0; i < 20; i++ {
+		actual, err := buildDashboardBodyJson(context.Background(), dashboardDataSourceModel{}, widgets)
+		require.NoError(t, err)
+		assert.Equal(t, expected, actual)
+	}
+}
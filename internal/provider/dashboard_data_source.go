@@ -3,14 +3,17 @@ package provider
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/Code-Hex/synchro/iso8601"
 	"github.com/hashicorp/terraform-plugin-framework/attr"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/hashicorp/terraform-plugin-log/tflog"
 )
@@ -36,10 +39,46 @@ func (d *dashboardDataSource) Schema(_ context.Context, _ datasource.SchemaReque
 	resp.Schema = schema.Schema{
 		Attributes: map[string]schema.Attribute{
 			"widgets": schema.ListAttribute{
-				Description: `The list of widgets in the dashboard.`,
-				Required:    true,
+				Description: `The list of widgets in the dashboard. Rows, columns, sections and spacers (` + "`cwdashboard_row`" + `, ` + "`cwdashboard_column`" + `, ` + "`cwdashboard_section`" + ` and ` + "`cwdashboard_spacer`" + `) can be used in place of widgets. ` +
+					`One of ` + "`widgets`" + ` or ` + "`layout`" + ` is required.`,
+				Optional:    true,
 				ElementType: types.StringType,
 			},
+			"layout": schema.StringAttribute{
+				Description: `An ASCII-art grid that places the widgets of ` + "`layout_widgets`" + `, for example ` + "`\"AAAB\\nCCDD\"`" + `. ` +
+					`Every letter is a widget, and the rectangle it covers gives the position and the size of the widget. ` +
+					`The columns split the width of the dashboard evenly, and ` + "`.`" + ` leaves a cell empty. ` +
+					`Cannot be used together with ` + "`widgets`" + `.`,
+				Optional: true,
+			},
+			"layout_widgets": schema.MapAttribute{
+				Description: `The widgets placed by ` + "`layout`" + `, keyed by their letter in the grid. The width and height of the widgets are ignored, and x and y cannot be set.`,
+				Optional:    true,
+				ElementType: types.StringType,
+			},
+			"layout_row_height": schema.Int32Attribute{
+				Description: `Height of every line of ` + "`layout`" + `. Defaults to ` + "`6`" + `.`,
+				Optional:    true,
+			},
+			"columns": schema.Int32Attribute{
+				Description: `The number of widgets in a row, used to size the widgets without a width. ` +
+					`A row has at most ` + "`columns`" + ` widgets, and a widget that does not fit in the width left, like a full-width header, starts a new row. ` +
+					`The widgets without a width split the width left in their row evenly. ` +
+					`The rows are counted in the order of ` + "`widgets`" + ` as ` + "`flow`" + ` places them, leaving out the widgets with x and y, ` +
+					`so the widths do not follow the pinned widgets, nor where ` + "`compact`" + ` and ` + "`masonry`" + ` move the widgets. ` +
+					`A pinned widget without a width takes the width of one column. ` +
+					`Defaults to ` + "`1`" + `, so that the widgets without a width take the full width of the dashboard.`,
+				Optional: true,
+			},
+			"layout_mode": schema.StringAttribute{
+				Description: `How the widgets without x and y are placed. ` +
+					"`flow`" + ` places them from left to right, starting a new row below the tallest widget of the row. ` +
+					"`compact`" + ` flows them, and then moves each of them up into the free space above it, as the CloudWatch console does. ` +
+					"`masonry`" + ` places each of them where the columns it covers are the lowest. ` +
+					`Defaults to ` + "`flow`" + `. ` +
+					`Valid Values: ` + "`flow`" + ` | ` + "`compact`" + ` | ` + "`masonry`",
+				Optional: true,
+			},
 			"end": schema.StringAttribute{
 				Description: `The end of the time range to use for each widget on the dashboard when the dashboard loads. ` +
 					`If you specify a value for end, you must also specify a value for ` + "`start`" + `. ` +
@@ -66,20 +105,134 @@ func (d *dashboardDataSource) Schema(_ context.Context, _ datasource.SchemaReque
 					`Valid Values: ` + "`auto`" + ` |` + "`inherit`",
 				Optional: true,
 			},
+			"variables": schema.ListNestedAttribute{
+				Description: `Dashboard variables, which let viewers switch the metrics shown by the widgets from a single dashboard.`,
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"type": schema.StringAttribute{
+							Description: `The type of the variable. ` +
+								"`property`" + ` changes the value of a dimension or property in every widget, ` +
+								"`pattern`" + ` replaces a string pattern wherever it appears in the widgets. ` +
+								`Valid Values: ` + "`property`" + ` | ` + "`pattern`",
+							Required: true,
+						},
+						"property": schema.StringAttribute{
+							Description: `The dimension name or property the variable changes. Required when ` + "`type`" + ` is ` + "`property`" + `.`,
+							Optional:    true,
+						},
+						"pattern": schema.StringAttribute{
+							Description: `The string pattern the variable replaces. Required when ` + "`type`" + ` is ` + "`pattern`" + `.`,
+							Optional:    true,
+						},
+						"input_type": schema.StringAttribute{
+							Description: `How the viewer chooses the value. ` +
+								`Valid Values: ` + "`input`" + ` | ` + "`select`" + ` | ` + "`radio`",
+							Required: true,
+						},
+						"id": schema.StringAttribute{
+							Description: `The unique identifier of the variable`,
+							Required:    true,
+						},
+						"label": schema.StringAttribute{
+							Description: `The label shown next to the variable input`,
+							Optional:    true,
+						},
+						"default_value": schema.StringAttribute{
+							Description: `The value used when the dashboard loads`,
+							Optional:    true,
+						},
+						"visible": schema.BoolAttribute{
+							Description: `Whether the variable input is shown on the dashboard. Defaults to ` + "`true`" + `.`,
+							Optional:    true,
+						},
+						"search": schema.StringAttribute{
+							Description: `A SEARCH expression whose results populate the values of the variable. Must be used with ` + "`populate_from`" + `.`,
+							Optional:    true,
+						},
+						"populate_from": schema.StringAttribute{
+							Description: `The dimension name of the ` + "`search`" + ` results used as the values of the variable`,
+							Optional:    true,
+						},
+						"values": schema.ListNestedAttribute{
+							Description: `The static values the viewer can choose from`,
+							Optional:    true,
+							NestedObject: schema.NestedAttributeObject{
+								Attributes: map[string]schema.Attribute{
+									"value": schema.StringAttribute{
+										Description: `The value`,
+										Required:    true,
+									},
+									"label": schema.StringAttribute{
+										Description: `The label shown for the value`,
+										Optional:    true,
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+			"table_of_contents": schema.SingleNestedAttribute{
+				Description: `Adds a text widget at the top of the dashboard that lists the title of every section (` + "`cwdashboard_section`" + `). ` +
+					`The widgets with x and y, and the widgets placed by ` + "`layout`" + `, are moved down by the height of the table of contents.`,
+				Optional: true,
+				Attributes: map[string]schema.Attribute{
+					"title": schema.StringAttribute{
+						Description: `The heading of the table of contents. Defaults to ` + "`Contents`" + `.`,
+						Optional:    true,
+					},
+					"height": schema.Int32Attribute{
+						Description: `Height of the table of contents. Defaults to the number of sections plus one.`,
+						Optional:    true,
+					},
+				},
+			},
 			"json": schema.StringAttribute{
-				Description: "The json of the dashboard body",
+				Description: "The json of the dashboard body. The same inputs always give the same json, so the dashboard is not updated without changes",
 				Computed:    true,
 			},
 		},
 	}
 }
 
+type dashboardVariableValueDataSourceModel struct {
+	Value types.String `tfsdk:"value"`
+	Label types.String `tfsdk:"label"`
+}
+
+type dashboardVariableDataSourceModel struct {
+	Type         types.String                            `tfsdk:"type"`
+	Property     types.String                            `tfsdk:"property"`
+	Pattern      types.String                            `tfsdk:"pattern"`
+	InputType    types.String                            `tfsdk:"input_type"`
+	Id           types.String                            `tfsdk:"id"`
+	Label        types.String                            `tfsdk:"label"`
+	DefaultValue types.String                            `tfsdk:"default_value"`
+	Visible      types.Bool                              `tfsdk:"visible"`
+	Search       types.String                            `tfsdk:"search"`
+	PopulateFrom types.String                            `tfsdk:"populate_from"`
+	Values       []dashboardVariableValueDataSourceModel `tfsdk:"values"`
+}
+
+type dashboardTableOfContentsDataSourceModel struct {
+	Title  types.String `tfsdk:"title"`
+	Height types.Int32  `tfsdk:"height"`
+}
+
 type dashboardDataSourceModel struct {
-	Start          types.String `tfsdk:"start"`
-	End            types.String `tfsdk:"end"`
-	PeriodOverride types.String `tfsdk:"period_override"`
-	Widgets        types.List   `tfsdk:"widgets"`
-	Json           types.String `tfsdk:"json"`
+	Start           types.String                             `tfsdk:"start"`
+	End             types.String                             `tfsdk:"end"`
+	PeriodOverride  types.String                             `tfsdk:"period_override"`
+	Widgets         types.List                               `tfsdk:"widgets"`
+	Layout          types.String                             `tfsdk:"layout"`
+	LayoutWidgets   map[string]types.String                  `tfsdk:"layout_widgets"`
+	LayoutRowHeight types.Int32                              `tfsdk:"layout_row_height"`
+	LayoutMode      types.String                             `tfsdk:"layout_mode"`
+	Columns         types.Int32                              `tfsdk:"columns"`
+	Variables       []dashboardVariableDataSourceModel       `tfsdk:"variables"`
+	TableOfContents *dashboardTableOfContentsDataSourceModel `tfsdk:"table_of_contents"`
+	Json            types.String                             `tfsdk:"json"`
 }
 
 const (
@@ -87,6 +240,19 @@ const (
 
 	periodOverrideAuto    = "auto"
 	periodOverrideInherit = "inherit"
+
+	dashboardVariableTypeProperty = "property"
+	dashboardVariableTypePattern  = "pattern"
+
+	dashboardVariableInputTypeInput  = "input"
+	dashboardVariableInputTypeSelect = "select"
+	dashboardVariableInputTypeRadio  = "radio"
+
+	dashboardTableOfContentsDefaultTitle = "Contents"
+
+	dashboardLayoutDefaultRowHeight = 6
+
+	dashboardDefaultColumns = 1
 )
 
 var (
@@ -98,8 +264,21 @@ var (
 )
 
 func (d *dashboardDataSourceModel) Validate() error {
-	if len(d.Widgets.Elements()) > dashboardMaxWidgets {
-		return fmt.Errorf("maximum number of widgets is %d. Got %d", dashboardMaxWidgets, len(d.Widgets.Elements()))
+	if count := len(d.Widgets.Elements()) + len(d.LayoutWidgets); count > dashboardMaxWidgets {
+		return fmt.Errorf("maximum number of widgets is %d. Got %d", dashboardMaxWidgets, count)
+	}
+
+	if !d.Layout.IsNull() {
+		if !d.Widgets.IsNull() {
+			return fmt.Errorf("widgets and layout cannot be used together")
+		}
+	} else if d.LayoutWidgets != nil || !d.LayoutRowHeight.IsNull() {
+		return fmt.Errorf("layout_widgets and layout_row_height can only be used with layout")
+	} else if d.Widgets.IsNull() {
+		return fmt.Errorf("one of widgets or layout is required")
+	}
+	if !d.LayoutRowHeight.IsNull() && d.LayoutRowHeight.ValueInt32() < 1 {
+		return fmt.Errorf("layout_row_height must be greater than 0, got: %d", d.LayoutRowHeight.ValueInt32())
 	}
 
 	// check if start is a valid ISO8601 date
@@ -129,6 +308,21 @@ func (d *dashboardDataSourceModel) Validate() error {
 		}
 	}
 
+	if !d.Columns.IsNull() {
+		if columns := d.Columns.ValueInt32(); columns < 1 || columns > MAX_WIDTH {
+			return fmt.Errorf("columns must be between 1 and %d, got: %d", MAX_WIDTH, columns)
+		}
+	}
+
+	// check if layout_mode is a valid value
+	if !d.LayoutMode.IsNull() {
+		switch d.LayoutMode.ValueString() {
+		case layoutModeFlow, layoutModeCompact, layoutModeMasonry:
+		default:
+			return fmt.Errorf("layout_mode must be one of 'flow', 'compact', or 'masonry', got: %s", d.LayoutMode.ValueString())
+		}
+	}
+
 	// check if period_override is a valid value
 	if !d.PeriodOverride.IsNull() {
 		if d.PeriodOverride.ValueString() != periodOverrideAuto && d.PeriodOverride.ValueString() != periodOverrideInherit {
@@ -136,6 +330,71 @@ func (d *dashboardDataSourceModel) Validate() error {
 		}
 	}
 
+	seenVariableIds := make(map[string]bool)
+	for i, v := range d.Variables {
+		if err := v.Validate(); err != nil {
+			return fmt.Errorf("invalid variable at index %d: %w", i, err)
+		}
+		if seenVariableIds[v.Id.ValueString()] {
+			return fmt.Errorf("duplicate variable id: %s", v.Id.ValueString())
+		}
+		seenVariableIds[v.Id.ValueString()] = true
+	}
+
+	if d.TableOfContents != nil && !d.TableOfContents.Height.IsNull() && d.TableOfContents.Height.ValueInt32() < 1 {
+		return fmt.Errorf("height of table_of_contents must be greater than 0, got: %d", d.TableOfContents.Height.ValueInt32())
+	}
+
+	return nil
+}
+
+func (v *dashboardVariableDataSourceModel) Validate() error {
+	if v.Id.ValueString() == "" {
+		return fmt.Errorf("id cannot be empty")
+	}
+
+	switch v.Type.ValueString() {
+	case dashboardVariableTypeProperty:
+		if v.Property.ValueString() == "" {
+			return fmt.Errorf("property is required when type is 'property'")
+		}
+		if !v.Pattern.IsNull() {
+			return fmt.Errorf("pattern cannot be set when type is 'property'")
+		}
+	case dashboardVariableTypePattern:
+		if v.Pattern.ValueString() == "" {
+			return fmt.Errorf("pattern is required when type is 'pattern'")
+		}
+		if !v.Property.IsNull() {
+			return fmt.Errorf("property cannot be set when type is 'pattern'")
+		}
+	default:
+		return fmt.Errorf("type must be either 'property' or 'pattern', got: %s", v.Type.ValueString())
+	}
+
+	hasSearch := !v.Search.IsNull()
+	hasValues := len(v.Values) > 0
+
+	if hasSearch && hasValues {
+		return fmt.Errorf("search and values cannot be used together")
+	}
+	if hasSearch != !v.PopulateFrom.IsNull() {
+		return fmt.Errorf("search and populate_from must be used together")
+	}
+
+	switch v.InputType.ValueString() {
+	case dashboardVariableInputTypeInput:
+		if hasSearch || hasValues {
+			return fmt.Errorf("search and values cannot be used when input_type is 'input'")
+		}
+	case dashboardVariableInputTypeSelect, dashboardVariableInputTypeRadio:
+		if !hasSearch && !hasValues {
+			return fmt.Errorf("either search or values is required when input_type is '%s'", v.InputType.ValueString())
+		}
+	default:
+		return fmt.Errorf("input_type must be one of 'input', 'select', or 'radio', got: %s", v.InputType.ValueString())
+	}
+
 	return nil
 }
 
@@ -152,15 +411,21 @@ func (d *dashboardDataSource) Read(ctx context.Context, req datasource.ReadReque
 		return
 	}
 
-	widgets, err := d.parseToWidgetSettings(ctx, state.Widgets.Elements())
+	var widgets []interface{}
+	var err error
+	if state.Layout.IsNull() {
+		widgets, err = d.parseToWidgetSettings(ctx, state.Widgets.Elements())
+	} else {
+		widgets, err = d.parseLayoutWidgetSettings(ctx, state)
+	}
 	if err != nil {
-		resp.Diagnostics.AddError("failed to parse widgets", err.Error())
+		addWidgetDiagnostic(resp, state, widgets, "failed to parse widgets", err)
 		return
 	}
 
 	dashboardJson, err := buildDashboardBodyJson(ctx, state, widgets)
 	if err != nil {
-		resp.Diagnostics.AddError("failed to build dashboard json", err.Error())
+		addWidgetDiagnostic(resp, state, widgets, "failed to build dashboard json", err)
 		return
 	}
 
@@ -177,54 +442,290 @@ func (d *dashboardDataSource) Read(ctx context.Context, req datasource.ReadReque
 	}
 }
 
+// widgetError is an error of the widget at Index in the widgets of the dashboard.
+type widgetError struct {
+	Index int
+	Err   error
+}
+
+func (e *widgetError) Error() string {
+	return fmt.Sprintf("invalid widget at index %d: %s", e.Index, e.Err)
+}
+
+func (e *widgetError) Unwrap() error {
+	return e.Err
+}
+
+// addWidgetDiagnostic adds the error to the diagnostics, pointing at the attribute of the widget when the error is about a widget.
+func addWidgetDiagnostic(resp *datasource.ReadResponse, state dashboardDataSourceModel, widgets []interface{}, summary string, err error) {
+	var we *widgetError
+	if !errors.As(err, &we) {
+		resp.Diagnostics.AddError(summary, err.Error())
+		return
+	}
+
+	attributePath := path.Root("widgets").AtListIndex(we.Index)
+	if !state.Layout.IsNull() {
+		w, ok := widgets[we.Index].(layoutTemplateWidgetSettings)
+		if !ok {
+			resp.Diagnostics.AddError(summary, err.Error())
+			return
+		}
+		attributePath = path.Root("layout_widgets").AtMapKey(w.Letter)
+	}
+
+	resp.Diagnostics.AddAttributeError(attributePath, summary, err.Error())
+}
+
 func (d *dashboardDataSource) parseToWidgetSettings(ctx context.Context, elements []attr.Value) ([]interface{}, error) {
 	widgets := make([]interface{}, 0)
-	var currentPosition *widgetPosition
-	for _, elem := range elements {
+	for i, elem := range elements {
 		// NOTE: Unmarshal twice because of double escaping by Terraform
 		var escaped string
 		if err := json.Unmarshal([]byte(elem.String()), &escaped); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal widget json: %w", err)
+			return nil, &widgetError{Index: i, Err: fmt.Errorf("failed to unmarshal widget json: %w", err)}
 		}
 
-		w := map[string]interface{}{}
-		if err := json.Unmarshal([]byte(escaped), &w); err != nil {
-			return nil, fmt.Errorf("failed to unmarshal widget json: %w", err)
+		w, err := parseWidgetSettings(ctx, []byte(escaped))
+		if err != nil {
+			return nil, &widgetError{Index: i, Err: err}
 		}
+		widgets = append(widgets, w)
+	}
+
+	return widgets, nil
+}
+
+// parseLayoutWidgetSettings parses the widgets of layout_widgets, and places each of them in the region of its letter in layout.
+func (d *dashboardDataSource) parseLayoutWidgetSettings(ctx context.Context, state dashboardDataSourceModel) ([]interface{}, error) {
+	rowHeight := int32(dashboardLayoutDefaultRowHeight)
+	if !state.LayoutRowHeight.IsNull() {
+		rowHeight = state.LayoutRowHeight.ValueInt32()
+	}
+
+	regions, err := parseLayoutTemplate(state.Layout.ValueString(), rowHeight)
+	if err != nil {
+		return nil, fmt.Errorf("invalid layout: %w", err)
+	}
 
-		widgetType, ok := w["type"].(string)
+	widgets := make([]interface{}, 0, len(regions))
+	used := make(map[string]bool)
+	for _, region := range regions {
+		widgetJson, ok := state.LayoutWidgets[region.Letter]
 		if !ok {
-			return nil, fmt.Errorf("missing widget type")
+			return nil, fmt.Errorf("unknown letter %q in layout", region.Letter)
 		}
+		used[region.Letter] = true
 
-		switch widgetType {
-		case "text":
-			var w textWidgetDataSourceSettings
-			if err := json.Unmarshal([]byte(escaped), &w); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal text widget json: %w", err)
-			}
-			widget, err := w.ToCWDashboardBodyWidget(ctx, w, currentPosition)
+		w, err := parseWidgetSettings(ctx, []byte(widgetJson.ValueString()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse widget %q of layout: %w", region.Letter, err)
+		}
+		widgets = append(widgets, layoutTemplateWidgetSettings{Letter: region.Letter, Rect: region.Rect, Widget: w})
+	}
+
+	letters := make([]string, 0, len(state.LayoutWidgets))
+	for letter := range state.LayoutWidgets {
+		letters = append(letters, letter)
+	}
+	sort.Strings(letters)
+	for _, letter := range letters {
+		if !used[letter] {
+			return nil, fmt.Errorf("letter %q of layout_widgets is not used in layout", letter)
+		}
+	}
+
+	return widgets, nil
+}
+
+// layoutTemplateWidgetSettings is a widget placed by the layout template of the dashboard.
+type layoutTemplateWidgetSettings struct {
+	Letter string
+	Rect   widgetRect
+	Widget interface{}
+}
+
+func (w layoutTemplateWidgetSettings) ToCWDashboardBodyWidget(ctx context.Context) (CWDashboardBodyWidget, error) {
+	widget, err := buildWidget(ctx, w.Widget)
+	if err != nil {
+		return CWDashboardBodyWidget{}, err
+	}
+	if widget.Pinned {
+		return CWDashboardBodyWidget{}, fmt.Errorf("widget placed by layout cannot have x and y")
+	}
+
+	if err := resizeWidget(&widget, w.Rect.Width); err != nil {
+		return CWDashboardBodyWidget{}, err
+	}
+	if len(widget.Children) > 0 && widget.Height > w.Rect.Height {
+		return CWDashboardBodyWidget{}, fmt.Errorf("%s needs a height of %d, but the region in layout is %d high", widget.Type, widget.Height, w.Rect.Height)
+	}
+
+	widget.X = w.Rect.X
+	widget.Y = w.Rect.Y
+	widget.Height = w.Rect.Height
+	widget.Pinned = true
+
+	return widget, nil
+}
+
+// parseWidgetSettings parses the json of a widget data source into its settings.
+func parseWidgetSettings(ctx context.Context, widgetJson []byte) (interface{}, error) {
+	w := map[string]interface{}{}
+	if err := json.Unmarshal(widgetJson, &w); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal widget json: %w", err)
+	}
+
+	widgetType, ok := w["type"].(string)
+	if !ok {
+		return nil, fmt.Errorf("missing widget type")
+	}
+
+	switch widgetType {
+	case "text":
+		var w textWidgetDataSourceSettings
+		if err := json.Unmarshal(widgetJson, &w); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal text widget json: %w", err)
+		}
+		if _, err := w.ToCWDashboardBodyWidget(ctx, w); err != nil {
+			return nil, fmt.Errorf("failed to parse text widget: %w", err)
+		}
+		return w, nil
+	case "graph":
+		var w graphWidgetDataSourceSettings
+		if err := json.Unmarshal(widgetJson, &w); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal graph widget json: %w", err)
+		}
+
+		if _, err := w.ToCWDashboardBodyWidget(ctx); err != nil {
+			return nil, fmt.Errorf("failed to parse graph widget: %w", err)
+		}
+		return w, nil
+	case "log":
+		var w logWidgetDataSourceSettings
+		if err := json.Unmarshal(widgetJson, &w); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal log widget json: %w", err)
+		}
+
+		if _, err := w.ToCWDashboardBodyWidget(ctx); err != nil {
+			return nil, fmt.Errorf("failed to parse log widget: %w", err)
+		}
+		return w, nil
+	case "alarm_status":
+		var w alarmStatusWidgetDataSourceSettings
+		if err := json.Unmarshal(widgetJson, &w); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal alarm status widget json: %w", err)
+		}
+
+		if _, err := w.ToCWDashboardBodyWidget(ctx); err != nil {
+			return nil, fmt.Errorf("failed to parse alarm status widget: %w", err)
+		}
+		return w, nil
+	case "explorer":
+		var w explorerWidgetDataSourceSettings
+		if err := json.Unmarshal(widgetJson, &w); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal explorer widget json: %w", err)
+		}
+
+		if _, err := w.ToCWDashboardBodyWidget(ctx); err != nil {
+			return nil, fmt.Errorf("failed to parse explorer widget: %w", err)
+		}
+		return w, nil
+	case "alarm":
+		var w alarmWidgetDataSourceSettings
+		if err := json.Unmarshal(widgetJson, &w); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal alarm widget json: %w", err)
+		}
+
+		if _, err := w.ToCWDashboardBodyWidget(ctx); err != nil {
+			return nil, fmt.Errorf("failed to parse alarm widget: %w", err)
+		}
+		return w, nil
+	case "single_value":
+		var w singleValueWidgetDataSourceSettings
+		if err := json.Unmarshal(widgetJson, &w); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal single value widget json: %w", err)
+		}
+
+		if _, err := w.ToCWDashboardBodyWidget(ctx); err != nil {
+			return nil, fmt.Errorf("failed to parse single value widget: %w", err)
+		}
+		return w, nil
+	case "custom":
+		var w customWidgetDataSourceSettings
+		if err := json.Unmarshal(widgetJson, &w); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal custom widget json: %w", err)
+		}
+
+		if _, err := w.ToCWDashboardBodyWidget(ctx); err != nil {
+			return nil, fmt.Errorf("failed to parse custom widget: %w", err)
+		}
+		return w, nil
+	case "spacer":
+		var w spacerDataSourceSettings
+		if err := json.Unmarshal(widgetJson, &w); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal spacer json: %w", err)
+		}
+
+		if _, err := w.ToCWDashboardBodyWidget(ctx); err != nil {
+			return nil, fmt.Errorf("failed to parse spacer: %w", err)
+		}
+		return w, nil
+	case "row":
+		var w rowDataSourceSettings
+		if err := json.Unmarshal(widgetJson, &w); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal row json: %w", err)
+		}
+
+		for i, child := range w.Widgets {
+			c, err := parseWidgetSettings(ctx, child)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse text widget: %w", err)
+				return nil, fmt.Errorf("failed to parse widget at index %d of row: %w", i, err)
 			}
-			currentPosition = &widgetPosition{X: widget.X, Y: widget.Y}
-			widgets = append(widgets, w)
-		case "graph":
-			var w graphWidgetDataSourceSettings
-			if err := json.Unmarshal([]byte(escaped), &w); err != nil {
-				return nil, fmt.Errorf("failed to unmarshal graph widget json: %w", err)
+			w.Children = append(w.Children, c)
+		}
+
+		if _, err := w.ToCWDashboardBodyWidget(ctx); err != nil {
+			return nil, fmt.Errorf("failed to parse row: %w", err)
+		}
+		return w, nil
+	case "column":
+		var w columnDataSourceSettings
+		if err := json.Unmarshal(widgetJson, &w); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal column json: %w", err)
+		}
+
+		for i, child := range w.Widgets {
+			c, err := parseWidgetSettings(ctx, child)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse widget at index %d of column: %w", i, err)
 			}
+			w.Children = append(w.Children, c)
+		}
 
-			widget, err := w.ToCWDashboardBodyWidget(ctx, currentPosition)
+		if _, err := w.ToCWDashboardBodyWidget(ctx); err != nil {
+			return nil, fmt.Errorf("failed to parse column: %w", err)
+		}
+		return w, nil
+	case "section":
+		var w sectionDataSourceSettings
+		if err := json.Unmarshal(widgetJson, &w); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal section json: %w", err)
+		}
+
+		for i, child := range w.Widgets {
+			c, err := parseWidgetSettings(ctx, child)
 			if err != nil {
-				return nil, fmt.Errorf("failed to parse graph widget: %w", err)
+				return nil, fmt.Errorf("failed to parse widget at index %d of section: %w", i, err)
 			}
-			currentPosition = &widgetPosition{X: widget.X, Y: widget.Y}
-			widgets = append(widgets, w)
-		default:
-			return nil, fmt.Errorf("unsupported widget type")
+			w.Children = append(w.Children, c)
 		}
-	}
 
-	return widgets, nil
+		if _, err := w.ToCWDashboardBodyWidget(ctx); err != nil {
+			return nil, fmt.Errorf("failed to parse section: %w", err)
+		}
+		return w, nil
+	default:
+		return nil, fmt.Errorf("unsupported widget type")
+	}
 }
@@ -1,11 +1,18 @@
 package provider
 
 import (
+	"context"
+	"encoding/json"
+	"errors"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/diag"
+	"github.com/hashicorp/terraform-plugin-framework/path"
 	"github.com/hashicorp/terraform-plugin-framework/types"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDashboardDataSourceModel_Validate(t *testing.T) {
@@ -39,6 +46,7 @@ func TestDashboardDataSourceModel_Validate(t *testing.T) {
 		{
 			name: "valid model with hours relative time for start",
 			model: dashboardDataSourceModel{
+				Widgets:        types.ListValueMust(types.StringType, []attr.Value{}),
 				Start:          types.StringValue("-PT2H"),
 				End:            types.StringValue("2024-01-02T00:00:00Z"),
 				PeriodOverride: types.StringValue("auto"),
@@ -48,6 +56,7 @@ func TestDashboardDataSourceModel_Validate(t *testing.T) {
 		{
 			name: "valid model with days relative time for start",
 			model: dashboardDataSourceModel{
+				Widgets:        types.ListValueMust(types.StringType, []attr.Value{}),
 				Start:          types.StringValue("-P7D"),
 				End:            types.StringValue("2024-01-02T00:00:00Z"),
 				PeriodOverride: types.StringValue("auto"),
@@ -57,6 +66,7 @@ func TestDashboardDataSourceModel_Validate(t *testing.T) {
 		{
 			name: "valid model with weeks relative time for start",
 			model: dashboardDataSourceModel{
+				Widgets:        types.ListValueMust(types.StringType, []attr.Value{}),
 				Start:          types.StringValue("-P2W"),
 				End:            types.StringValue("2024-01-02T00:00:00Z"),
 				PeriodOverride: types.StringValue("auto"),
@@ -66,6 +76,7 @@ func TestDashboardDataSourceModel_Validate(t *testing.T) {
 		{
 			name: "valid model with months relative time for start",
 			model: dashboardDataSourceModel{
+				Widgets:        types.ListValueMust(types.StringType, []attr.Value{}),
 				Start:          types.StringValue("-P3M"),
 				End:            types.StringValue("2024-01-02T00:00:00Z"),
 				PeriodOverride: types.StringValue("auto"),
@@ -75,7 +86,8 @@ func TestDashboardDataSourceModel_Validate(t *testing.T) {
 		{
 			name: "invalid relative time format for start (invalid minutes)",
 			model: dashboardDataSourceModel{
-				Start: types.StringValue("-PT15X"),
+				Widgets: types.ListValueMust(types.StringType, []attr.Value{}),
+				Start:   types.StringValue("-PT15X"),
 			},
 			wantErr: true,
 			errMsg:  "start must be a valid ISO8601 date or a valid relative time",
@@ -83,7 +95,8 @@ func TestDashboardDataSourceModel_Validate(t *testing.T) {
 		{
 			name: "invalid relative time format for start (invalid days)",
 			model: dashboardDataSourceModel{
-				Start: types.StringValue("-P7X"),
+				Widgets: types.ListValueMust(types.StringType, []attr.Value{}),
+				Start:   types.StringValue("-P7X"),
 			},
 			wantErr: true,
 			errMsg:  "start must be a valid ISO8601 date or a valid relative time",
@@ -91,7 +104,8 @@ func TestDashboardDataSourceModel_Validate(t *testing.T) {
 		{
 			name: "invalid start date format",
 			model: dashboardDataSourceModel{
-				Start: types.StringValue("invalid-date"),
+				Widgets: types.ListValueMust(types.StringType, []attr.Value{}),
+				Start:   types.StringValue("invalid-date"),
 			},
 			wantErr: true,
 			errMsg:  "start must be a valid ISO8601 date or a valid relative time",
@@ -99,7 +113,8 @@ func TestDashboardDataSourceModel_Validate(t *testing.T) {
 		{
 			name: "invalid end date format",
 			model: dashboardDataSourceModel{
-				End: types.StringValue("invalid-date"),
+				Widgets: types.ListValueMust(types.StringType, []attr.Value{}),
+				End:     types.StringValue("invalid-date"),
 			},
 			wantErr: true,
 			errMsg:  "end must be a valid ISO8601 date",
@@ -107,6 +122,7 @@ func TestDashboardDataSourceModel_Validate(t *testing.T) {
 		{
 			name: "invalid period override value",
 			model: dashboardDataSourceModel{
+				Widgets:        types.ListValueMust(types.StringType, []attr.Value{}),
 				PeriodOverride: types.StringValue("invalid"),
 			},
 			wantErr: true,
@@ -123,6 +139,7 @@ func TestDashboardDataSourceModel_Validate(t *testing.T) {
 		{
 			name: "valid model with inherit period override",
 			model: dashboardDataSourceModel{
+				Widgets:        types.ListValueMust(types.StringType, []attr.Value{}),
 				PeriodOverride: types.StringValue("inherit"),
 			},
 			wantErr: false,
@@ -130,8 +147,200 @@ func TestDashboardDataSourceModel_Validate(t *testing.T) {
 		{
 			name:    "empty model",
 			model:   dashboardDataSourceModel{},
+			wantErr: true,
+			errMsg:  "one of widgets or layout is required",
+		},
+		{
+			name: "valid property variable with static values",
+			model: dashboardDataSourceModel{
+				Widgets: types.ListValueMust(types.StringType, []attr.Value{}),
+				Variables: []dashboardVariableDataSourceModel{
+					{
+						Type:      types.StringValue("property"),
+						Property:  types.StringValue("InstanceId"),
+						InputType: types.StringValue("select"),
+						Id:        types.StringValue("instance"),
+						Values: []dashboardVariableValueDataSourceModel{
+							{Value: types.StringValue("i-0123456789abcdef0"), Label: types.StringValue("web-1")},
+						},
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid pattern variable populated from search",
+			model: dashboardDataSourceModel{
+				Widgets: types.ListValueMust(types.StringType, []attr.Value{}),
+				Variables: []dashboardVariableDataSourceModel{
+					{
+						Type:         types.StringValue("pattern"),
+						Pattern:      types.StringValue("i-0123456789abcdef0"),
+						InputType:    types.StringValue("radio"),
+						Id:           types.StringValue("instance"),
+						Search:       types.StringValue(`{AWS/EC2,InstanceId} MetricName="CPUUtilization"`),
+						PopulateFrom: types.StringValue("InstanceId"),
+					},
+				},
+			},
 			wantErr: false,
 		},
+		{
+			name: "invalid variable type",
+			model: dashboardDataSourceModel{
+				Widgets: types.ListValueMust(types.StringType, []attr.Value{}),
+				Variables: []dashboardVariableDataSourceModel{
+					{
+						Type:      types.StringValue("dimension"),
+						InputType: types.StringValue("input"),
+						Id:        types.StringValue("instance"),
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid variable at index 0: type must be either 'property' or 'pattern', got: dimension",
+		},
+		{
+			name: "property variable without property",
+			model: dashboardDataSourceModel{
+				Widgets: types.ListValueMust(types.StringType, []attr.Value{}),
+				Variables: []dashboardVariableDataSourceModel{
+					{
+						Type:      types.StringValue("property"),
+						InputType: types.StringValue("input"),
+						Id:        types.StringValue("instance"),
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid variable at index 0: property is required when type is 'property'",
+		},
+		{
+			name: "select variable without values or search",
+			model: dashboardDataSourceModel{
+				Widgets: types.ListValueMust(types.StringType, []attr.Value{}),
+				Variables: []dashboardVariableDataSourceModel{
+					{
+						Type:      types.StringValue("property"),
+						Property:  types.StringValue("InstanceId"),
+						InputType: types.StringValue("select"),
+						Id:        types.StringValue("instance"),
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid variable at index 0: either search or values is required when input_type is 'select'",
+		},
+		{
+			name: "search without populate_from",
+			model: dashboardDataSourceModel{
+				Widgets: types.ListValueMust(types.StringType, []attr.Value{}),
+				Variables: []dashboardVariableDataSourceModel{
+					{
+						Type:      types.StringValue("property"),
+						Property:  types.StringValue("InstanceId"),
+						InputType: types.StringValue("select"),
+						Id:        types.StringValue("instance"),
+						Search:    types.StringValue(`{AWS/EC2,InstanceId} MetricName="CPUUtilization"`),
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid variable at index 0: search and populate_from must be used together",
+		},
+		{
+			name: "invalid input type",
+			model: dashboardDataSourceModel{
+				Widgets: types.ListValueMust(types.StringType, []attr.Value{}),
+				Variables: []dashboardVariableDataSourceModel{
+					{
+						Type:      types.StringValue("property"),
+						Property:  types.StringValue("InstanceId"),
+						InputType: types.StringValue("text"),
+						Id:        types.StringValue("instance"),
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "invalid variable at index 0: input_type must be one of 'input', 'select', or 'radio', got: text",
+		},
+		{
+			name: "duplicate variable ids",
+			model: dashboardDataSourceModel{
+				Widgets: types.ListValueMust(types.StringType, []attr.Value{}),
+				Variables: []dashboardVariableDataSourceModel{
+					{
+						Type:      types.StringValue("property"),
+						Property:  types.StringValue("InstanceId"),
+						InputType: types.StringValue("input"),
+						Id:        types.StringValue("instance"),
+					},
+					{
+						Type:      types.StringValue("property"),
+						Property:  types.StringValue("AutoScalingGroupName"),
+						InputType: types.StringValue("input"),
+						Id:        types.StringValue("instance"),
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "duplicate variable id: instance",
+		},
+		{
+			name: "too many columns",
+			model: dashboardDataSourceModel{
+				Widgets: types.ListValueMust(types.StringType, []attr.Value{}),
+				Columns: types.Int32Value(25),
+			},
+			wantErr: true,
+			errMsg:  "columns must be between 1 and 24, got: 25",
+		},
+		{
+			name: "invalid layout mode",
+			model: dashboardDataSourceModel{
+				Widgets:    types.ListValueMust(types.StringType, []attr.Value{}),
+				LayoutMode: types.StringValue("grid"),
+			},
+			wantErr: true,
+			errMsg:  "layout_mode must be one of 'flow', 'compact', or 'masonry', got: grid",
+		},
+		{
+			name: "widgets and layout",
+			model: dashboardDataSourceModel{
+				Widgets: types.ListValueMust(types.StringType, []attr.Value{}),
+				Layout:  types.StringValue("AB"),
+			},
+			wantErr: true,
+			errMsg:  "widgets and layout cannot be used together",
+		},
+		{
+			name: "layout widgets without layout",
+			model: dashboardDataSourceModel{
+				LayoutWidgets: map[string]types.String{"A": types.StringValue(`{"type":"text"}`)},
+			},
+			wantErr: true,
+			errMsg:  "layout_widgets and layout_row_height can only be used with layout",
+		},
+		{
+			name: "zero layout row height",
+			model: dashboardDataSourceModel{
+				Layout:          types.StringValue("AB"),
+				LayoutRowHeight: types.Int32Value(0),
+			},
+			wantErr: true,
+			errMsg:  "layout_row_height must be greater than 0, got: 0",
+		},
+		{
+			name: "table of contents with zero height",
+			model: dashboardDataSourceModel{
+				Widgets: types.ListValueMust(types.StringType, []attr.Value{}),
+				TableOfContents: &dashboardTableOfContentsDataSourceModel{
+					Height: types.Int32Value(0),
+				},
+			},
+			wantErr: true,
+			errMsg:  "height of table_of_contents must be greater than 0, got: 0",
+		},
 	}
 
 	for _, tt := range tests {
@@ -155,3 +364,189 @@ func createWidgetsList(count int) types.List {
 	}
 	return types.ListValueMust(types.StringType, elements)
 }
+
+func TestParseWidgetSettings_Groups(t *testing.T) {
+	t.Run("should parse nested rows and columns", func(t *testing.T) {
+		widgetJson := `{"type":"row","widgets":[` +
+			`{"type":"text","markdown":"a","width":8,"height":2},` +
+			`{"type":"column","widgets":[{"type":"text","markdown":"b","width":8,"height":2},{"type":"text","markdown":"c","width":8,"height":2}]}` +
+			`]}`
+
+		actual, err := parseWidgetSettings(context.Background(), []byte(widgetJson))
+		assert.NoError(t, err)
+
+		row, ok := actual.(rowDataSourceSettings)
+		assert.True(t, ok)
+		assert.Len(t, row.Children, 2)
+		assert.IsType(t, textWidgetDataSourceSettings{}, row.Children[0])
+
+		column, ok := row.Children[1].(columnDataSourceSettings)
+		assert.True(t, ok)
+		assert.Len(t, column.Children, 2)
+	})
+
+	t.Run("should fail on an unsupported widget in a column", func(t *testing.T) {
+		widgetJson := `{"type":"column","widgets":[{"type":"unknown"}]}`
+
+		_, err := parseWidgetSettings(context.Background(), []byte(widgetJson))
+		assert.EqualError(t, err, "failed to parse widget at index 0 of column: unsupported widget type")
+	})
+}
+
+func TestParseWidgetSettings_Section(t *testing.T) {
+	widgetJson := `{"type":"section","title":"Database","widgets":[` +
+		`{"type":"text","markdown":"a","width":8,"height":2},` +
+		`{"type":"row","widgets":[{"type":"text","markdown":"b","width":8,"height":2}]}` +
+		`]}`
+
+	actual, err := parseWidgetSettings(context.Background(), []byte(widgetJson))
+	assert.NoError(t, err)
+
+	section, ok := actual.(sectionDataSourceSettings)
+	assert.True(t, ok)
+	assert.Equal(t, "Database", section.Title)
+	assert.Len(t, section.Children, 2)
+	assert.IsType(t, rowDataSourceSettings{}, section.Children[1])
+}
+
+func TestDashboardDataSource_ParseLayoutWidgetSettings(t *testing.T) {
+	text := types.StringValue(`{"type":"text","markdown":"a","width":1,"height":1}`)
+	row := types.StringValue(`{"type":"row","widgets":[{"type":"text","markdown":"b","width":1,"height":1},{"type":"text","markdown":"c","width":1,"height":2}]}`)
+
+	t.Run("should place the widgets in the regions of their letters", func(t *testing.T) {
+		state := dashboardDataSourceModel{
+			Layout:          types.StringValue("AAAB\nCCDD"),
+			LayoutWidgets:   map[string]types.String{"A": text, "B": text, "C": row, "D": text},
+			LayoutRowHeight: types.Int32Value(4),
+		}
+
+		widgets, err := (&dashboardDataSource{}).parseLayoutWidgetSettings(context.Background(), state)
+		require.NoError(t, err)
+
+		actual, err := buildDashboardBodyJson(context.Background(), state, widgets)
+		require.NoError(t, err)
+
+		var body CWDashboardBody
+		require.NoError(t, json.Unmarshal([]byte(actual), &body))
+
+		rects := make([]widgetRect, 0, len(body.Widgets))
+		for _, w := range body.Widgets {
+			rects = append(rects, widgetRect{widgetPosition{X: w.X, Y: w.Y}, widgetSize{Width: w.Width, Height: w.Height}})
+		}
+		assert.Equal(t, []widgetRect{
+			{widgetPosition{X: 0, Y: 0}, widgetSize{Width: 18, Height: 4}},
+			{widgetPosition{X: 18, Y: 0}, widgetSize{Width: 6, Height: 4}},
+			{widgetPosition{X: 0, Y: 4}, widgetSize{Width: 6, Height: 1}},
+			{widgetPosition{X: 6, Y: 4}, widgetSize{Width: 6, Height: 2}},
+			{widgetPosition{X: 12, Y: 4}, widgetSize{Width: 12, Height: 4}},
+		}, rects)
+	})
+
+	tests := []struct {
+		name   string
+		state  dashboardDataSourceModel
+		errMsg string
+	}{
+		{
+			name: "unknown letter",
+			state: dashboardDataSourceModel{
+				Layout:        types.StringValue("AB"),
+				LayoutWidgets: map[string]types.String{"A": text},
+			},
+			errMsg: `unknown letter "B" in layout`,
+		},
+		{
+			name: "unused letter",
+			state: dashboardDataSourceModel{
+				Layout:        types.StringValue("AA"),
+				LayoutWidgets: map[string]types.String{"A": text, "Z": text},
+			},
+			errMsg: `letter "Z" of layout_widgets is not used in layout`,
+		},
+		{
+			name: "non-rectangular region",
+			state: dashboardDataSourceModel{
+				Layout:        types.StringValue("AB\nBB"),
+				LayoutWidgets: map[string]types.String{"A": text, "B": text},
+			},
+			errMsg: `invalid layout: region of 'B' in layout is not a rectangle`,
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := (&dashboardDataSource{}).parseLayoutWidgetSettings(context.Background(), tc.state)
+			assert.EqualError(t, err, tc.errMsg)
+		})
+	}
+
+	t.Run("should fail when a row is higher than its region", func(t *testing.T) {
+		state := dashboardDataSourceModel{
+			Layout:          types.StringValue("A"),
+			LayoutWidgets:   map[string]types.String{"A": row},
+			LayoutRowHeight: types.Int32Value(1),
+		}
+
+		widgets, err := (&dashboardDataSource{}).parseLayoutWidgetSettings(context.Background(), state)
+		require.NoError(t, err)
+
+		_, err = buildDashboardBodyJson(context.Background(), state, widgets)
+		assert.EqualError(t, err, "invalid widget at index 0: failed to parse widget placed by layout: row needs a height of 2, but the region in layout is 1 high")
+	})
+}
+
+func TestAddWidgetDiagnostic(t *testing.T) {
+	t.Run("should point at the widget in widgets", func(t *testing.T) {
+		resp := &datasource.ReadResponse{}
+		err := &widgetError{Index: 2, Err: errors.New("width must be between 1 and 24, got: 30")}
+
+		addWidgetDiagnostic(resp, dashboardDataSourceModel{}, nil, "failed to build dashboard json", err)
+
+		require.Len(t, resp.Diagnostics, 1)
+		withPath, ok := resp.Diagnostics[0].(diag.DiagnosticWithPath)
+		require.True(t, ok)
+		assert.Equal(t, path.Root("widgets").AtListIndex(2), withPath.Path())
+		assert.Equal(t, "invalid widget at index 2: width must be between 1 and 24, got: 30", resp.Diagnostics[0].Detail())
+	})
+
+	t.Run("should point at the letter in layout_widgets", func(t *testing.T) {
+		resp := &datasource.ReadResponse{}
+		state := dashboardDataSourceModel{Layout: types.StringValue("AB")}
+		widgets := []interface{}{
+			layoutTemplateWidgetSettings{Letter: "A"},
+			layoutTemplateWidgetSettings{Letter: "B"},
+		}
+
+		addWidgetDiagnostic(resp, state, widgets, "failed to build dashboard json", &widgetError{Index: 1, Err: errors.New("invalid")})
+
+		require.Len(t, resp.Diagnostics, 1)
+		withPath, ok := resp.Diagnostics[0].(diag.DiagnosticWithPath)
+		require.True(t, ok)
+		assert.Equal(t, path.Root("layout_widgets").AtMapKey("B"), withPath.Path())
+	})
+
+	t.Run("should add a plain error for other errors", func(t *testing.T) {
+		resp := &datasource.ReadResponse{}
+
+		addWidgetDiagnostic(resp, dashboardDataSourceModel{}, nil, "failed to build dashboard json", errors.New("failed to lay out widgets"))
+
+		require.Len(t, resp.Diagnostics, 1)
+		_, ok := resp.Diagnostics[0].(diag.DiagnosticWithPath)
+		assert.False(t, ok)
+	})
+}
+
+func TestParseWidgetSettings_Spacer(t *testing.T) {
+	actual, err := parseWidgetSettings(context.Background(), []byte(`{"type":"spacer","width":6,"height":0}`))
+	assert.NoError(t, err)
+	assert.Equal(t, spacerDataSourceSettings{Type: "spacer", Width: 6}, actual)
+}
+
+func TestParseWidgetSettings_Enabled(t *testing.T) {
+	actual, err := parseWidgetSettings(context.Background(), []byte(`{"type":"graph","width":12,"height":6,"enabled":false}`))
+	require.NoError(t, err)
+
+	graph, ok := actual.(graphWidgetDataSourceSettings)
+	require.True(t, ok)
+	assert.Equal(t, ptr(false), graph.Enabled)
+}
@@ -0,0 +1,460 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource = &explorerWidgetDataSource{}
+)
+
+type explorerWidgetDataSource struct {
+}
+
+func NewExplorerWidgetDataSource() func() datasource.DataSource {
+	return func() datasource.DataSource {
+		return &explorerWidgetDataSource{}
+	}
+}
+
+func (d *explorerWidgetDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_explorer_widget"
+}
+
+func (d *explorerWidgetDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"metrics": schema.ListNestedAttribute{
+				Description: "The metrics to display. Each metric is graphed for every resource matching the labels.",
+				Required:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"metric_name": schema.StringAttribute{
+							Description: "Name of the metric",
+							Required:    true,
+						},
+						"resource_type": schema.StringAttribute{
+							Description: "The resource type of the metric (e.g. `AWS::EC2::Instance`)",
+							Required:    true,
+						},
+						"statistic": schema.StringAttribute{
+							Description: "What function to use for aggregating",
+							Required:    true,
+						},
+					},
+				},
+			},
+			"labels": schema.ListNestedAttribute{
+				Description: "The tags used to select the resources to display",
+				Required:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"key": schema.StringAttribute{
+							Description: "The tag key",
+							Required:    true,
+						},
+						"value": schema.StringAttribute{
+							Description: "The tag value. If omitted, every resource with the tag key is selected.",
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"aggregate_by": schema.SingleNestedAttribute{
+				Description: "Aggregates the metrics of the resources sharing the same tag value",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"key": schema.StringAttribute{
+						Description: "The tag key to aggregate by",
+						Required:    true,
+					},
+					"function": schema.StringAttribute{
+						Description: "The aggregation function. Valid Values: `AVG` | `MIN` | `MAX` | `SUM`",
+						Required:    true,
+					},
+				},
+			},
+			"split_by": schema.StringAttribute{
+				Description: "The tag key or property used to split the metrics into separate graphs",
+				Optional:    true,
+			},
+			"period": schema.Int32Attribute{
+				Description: "The period for the metrics in this widget",
+				Optional:    true,
+			},
+			"region": schema.StringAttribute{
+				Description: "The region the resources are taken from",
+				Optional:    true,
+			},
+			"title": schema.StringAttribute{
+				Description: "Title for the widget",
+				Optional:    true,
+			},
+			"rows_per_page": schema.Int32Attribute{
+				Description: "The number of rows of graphs to show on each page",
+				Optional:    true,
+			},
+			"widgets_per_row": schema.Int32Attribute{
+				Description: "The number of graphs to show in each row, between 1 and 4",
+				Optional:    true,
+			},
+			"view": schema.StringAttribute{
+				Description: "How the graphs are displayed. Valid Values: `timeSeries` | `bar` | `pie`",
+				Optional:    true,
+			},
+			"stacked": schema.BoolAttribute{
+				Description: "Whether the graphs should be shown as stacked lines",
+				Optional:    true,
+			},
+			"legend_position": schema.StringAttribute{
+				Description: "Position of the legend",
+				Optional:    true,
+			},
+			"width": schema.Int32Attribute{
+				Description: "Width of the widget, in a grid of 24 units wide. Defaults to an even share of its row, see `columns` of `cwdashboard`",
+				Optional:    true,
+			},
+			"height": schema.Int32Attribute{
+				Description: "Height of the widget. Defaults to `6`",
+				Optional:    true,
+			},
+			"enabled": schema.BoolAttribute{
+				Description: "Whether the widget is in the dashboard. Defaults to `true`. The other widgets are laid out as if a disabled widget was not there",
+				Optional:    true,
+			},
+			"x": schema.Int32Attribute{
+				Description: "The horizontal position of the widget, in a grid of 24 units wide. Must be set together with `y`. When omitted, the widget is placed automatically",
+				Optional:    true,
+			},
+			"y": schema.Int32Attribute{
+				Description: "The vertical position of the widget. Must be set together with `x`. When omitted, the widget is placed automatically",
+				Optional:    true,
+			},
+
+			"json": schema.StringAttribute{
+				Description: "The settings of the widget",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type explorerWidgetMetricDataSourceModel struct {
+	MetricName   types.String `tfsdk:"metric_name"`
+	ResourceType types.String `tfsdk:"resource_type"`
+	Statistic    types.String `tfsdk:"statistic"`
+}
+
+type explorerWidgetLabelDataSourceModel struct {
+	Key   types.String `tfsdk:"key"`
+	Value types.String `tfsdk:"value"`
+}
+
+type explorerWidgetAggregateByDataSourceModel struct {
+	Key      types.String `tfsdk:"key"`
+	Function types.String `tfsdk:"function"`
+}
+
+type explorerWidgetDataSourceModel struct {
+	Metrics        []explorerWidgetMetricDataSourceModel     `tfsdk:"metrics"`
+	Labels         []explorerWidgetLabelDataSourceModel      `tfsdk:"labels"`
+	AggregateBy    *explorerWidgetAggregateByDataSourceModel `tfsdk:"aggregate_by"`
+	SplitBy        types.String                              `tfsdk:"split_by"`
+	Period         types.Int32                               `tfsdk:"period"`
+	Region         types.String                              `tfsdk:"region"`
+	Title          types.String                              `tfsdk:"title"`
+	RowsPerPage    types.Int32                               `tfsdk:"rows_per_page"`
+	WidgetsPerRow  types.Int32                               `tfsdk:"widgets_per_row"`
+	View           types.String                              `tfsdk:"view"`
+	Stacked        types.Bool                                `tfsdk:"stacked"`
+	LegendPosition types.String                              `tfsdk:"legend_position"`
+	Width          types.Int32                               `tfsdk:"width"`
+	Height         types.Int32                               `tfsdk:"height"`
+	X              types.Int32                               `tfsdk:"x"`
+	Y              types.Int32                               `tfsdk:"y"`
+	Enabled        types.Bool                                `tfsdk:"enabled"`
+
+	Json types.String `tfsdk:"json"`
+}
+
+const (
+	explorerWidgetMaxWidgetsPerRow = 4
+)
+
+func (d *explorerWidgetDataSourceModel) Validate() error {
+	if err := validateWidgetSize(d.Width, d.Height); err != nil {
+		return err
+	}
+	if err := validateWidgetPosition(d.X, d.Y); err != nil {
+		return err
+	}
+
+	if len(d.Metrics) == 0 {
+		return fmt.Errorf("metrics must contain at least one metric")
+	}
+
+	for _, m := range d.Metrics {
+		stat := m.Statistic.ValueString()
+		if strings.HasPrefix(stat, "p") {
+			percentile, err := strconv.ParseFloat(strings.TrimPrefix(stat, "p"), 64)
+			if err != nil || percentile < 0 || percentile > 100 {
+				return fmt.Errorf("invalid percentile statistic: %s, must be between p0 and p100", stat)
+			}
+		} else if !validMetricStatistics[stat] {
+			return fmt.Errorf("invalid statistic: %s", stat)
+		}
+	}
+
+	if len(d.Labels) == 0 {
+		return fmt.Errorf("labels must contain at least one tag filter")
+	}
+
+	if d.AggregateBy != nil {
+		function := d.AggregateBy.Function.ValueString()
+		validFunctions := map[string]bool{
+			"AVG": true,
+			"MIN": true,
+			"MAX": true,
+			"SUM": true,
+		}
+		if !validFunctions[function] {
+			return fmt.Errorf("aggregate_by function must be one of 'AVG', 'MIN', 'MAX', or 'SUM', got: %s", function)
+		}
+	}
+
+	// Period must be 60 or a multiple of 60
+	if !d.Period.IsNull() {
+		if period := d.Period.ValueInt32(); period < 60 || period%60 != 0 {
+			return fmt.Errorf("period must be 60 or a multiple of 60, got: %d", period)
+		}
+	}
+
+	if !d.RowsPerPage.IsNull() {
+		if rowsPerPage := d.RowsPerPage.ValueInt32(); rowsPerPage < 1 {
+			return fmt.Errorf("rows_per_page must be greater than 0, got: %d", rowsPerPage)
+		}
+	}
+
+	if !d.WidgetsPerRow.IsNull() {
+		if widgetsPerRow := d.WidgetsPerRow.ValueInt32(); widgetsPerRow < 1 || widgetsPerRow > explorerWidgetMaxWidgetsPerRow {
+			return fmt.Errorf("widgets_per_row must be between 1 and %d, got: %d", explorerWidgetMaxWidgetsPerRow, widgetsPerRow)
+		}
+	}
+
+	if !d.View.IsNull() {
+		view := d.View.ValueString()
+		validViews := map[string]bool{
+			"timeSeries": true,
+			"bar":        true,
+			"pie":        true,
+		}
+		if !validViews[view] {
+			return fmt.Errorf("view must be one of 'timeSeries', 'bar', or 'pie', got: %s", view)
+		}
+	}
+
+	if !d.LegendPosition.IsNull() {
+		legendPos := d.LegendPosition.ValueString()
+		validPositions := map[string]bool{
+			"right":  true,
+			"bottom": true,
+			"hidden": true,
+		}
+		if !validPositions[legendPos] {
+			return fmt.Errorf("legend_position must be one of 'right', 'bottom', or 'hidden', got: %s", legendPos)
+		}
+	}
+
+	return nil
+}
+
+type explorerWidgetMetricDataSourceSettings struct {
+	MetricName   string `json:"metric_name"`
+	ResourceType string `json:"resource_type"`
+	Statistic    string `json:"statistic"`
+}
+
+type explorerWidgetLabelDataSourceSettings struct {
+	Key   string `json:"key"`
+	Value string `json:"value,omitempty"`
+}
+
+type explorerWidgetAggregateByDataSourceSettings struct {
+	Key      string `json:"key"`
+	Function string `json:"function"`
+}
+
+type explorerWidgetDataSourceSettings struct {
+	Type           string                                       `json:"type"`
+	Metrics        []explorerWidgetMetricDataSourceSettings     `json:"metrics"`
+	Labels         []explorerWidgetLabelDataSourceSettings      `json:"labels"`
+	AggregateBy    *explorerWidgetAggregateByDataSourceSettings `json:"aggregate_by,omitempty"`
+	SplitBy        string                                       `json:"split_by,omitempty"`
+	Period         int32                                        `json:"period,omitempty"`
+	Region         string                                       `json:"region,omitempty"`
+	Title          string                                       `json:"title,omitempty"`
+	RowsPerPage    int32                                        `json:"rows_per_page,omitempty"`
+	WidgetsPerRow  int32                                        `json:"widgets_per_row,omitempty"`
+	View           string                                       `json:"view,omitempty"`
+	Stacked        bool                                         `json:"stacked,omitempty"`
+	LegendPosition string                                       `json:"legend_position,omitempty"`
+	Width          int32                                        `json:"width"`
+	Height         int32                                        `json:"height"`
+	X              *int32                                       `json:"x,omitempty"`
+	Y              *int32                                       `json:"y,omitempty"`
+	Enabled        *bool                                        `json:"enabled,omitempty"`
+}
+
+const (
+	typeExplorerWidget = "explorer"
+
+	defaultExplorerWidgetHeight = 6
+)
+
+func (d *explorerWidgetDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state explorerWidgetDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := state.Validate(); err != nil {
+		resp.Diagnostics.AddError("invalid settings", err.Error())
+		return
+	}
+
+	metrics := make([]explorerWidgetMetricDataSourceSettings, len(state.Metrics))
+	for i, m := range state.Metrics {
+		metrics[i] = explorerWidgetMetricDataSourceSettings{
+			MetricName:   m.MetricName.ValueString(),
+			ResourceType: m.ResourceType.ValueString(),
+			Statistic:    m.Statistic.ValueString(),
+		}
+	}
+
+	labels := make([]explorerWidgetLabelDataSourceSettings, len(state.Labels))
+	for i, l := range state.Labels {
+		labels[i] = explorerWidgetLabelDataSourceSettings{
+			Key:   l.Key.ValueString(),
+			Value: l.Value.ValueString(),
+		}
+	}
+
+	settings := explorerWidgetDataSourceSettings{
+		Type:           typeExplorerWidget,
+		Metrics:        metrics,
+		Labels:         labels,
+		SplitBy:        state.SplitBy.ValueString(),
+		Period:         state.Period.ValueInt32(),
+		Region:         state.Region.ValueString(),
+		Title:          state.Title.ValueString(),
+		RowsPerPage:    state.RowsPerPage.ValueInt32(),
+		WidgetsPerRow:  state.WidgetsPerRow.ValueInt32(),
+		View:           state.View.ValueString(),
+		Stacked:        state.Stacked.ValueBool(),
+		LegendPosition: state.LegendPosition.ValueString(),
+		Width:          state.Width.ValueInt32(),
+		Height:         state.Height.ValueInt32(),
+		X:              state.X.ValueInt32Pointer(),
+		Y:              state.Y.ValueInt32Pointer(),
+		Enabled:        state.Enabled.ValueBoolPointer(),
+	}
+
+	if state.AggregateBy != nil {
+		settings.AggregateBy = &explorerWidgetAggregateByDataSourceSettings{
+			Key:      state.AggregateBy.Key.ValueString(),
+			Function: state.AggregateBy.Function.ValueString(),
+		}
+	}
+
+	b, err := json.Marshal(settings)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to marshal widget settings", err.Error())
+		return
+	}
+
+	tflog.Info(ctx, "explorer widget settings", map[string]interface{}{
+		"settings": string(b),
+	})
+
+	state.Json = types.StringValue(string(b))
+
+	stateDiags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(stateDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (w explorerWidgetDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Context) (CWDashboardBodyWidget, error) {
+	metrics := make([]CWDashboardBodyWidgetPropertyExplorerMetric, len(w.Metrics))
+	for i, m := range w.Metrics {
+		metrics[i] = CWDashboardBodyWidgetPropertyExplorerMetric{
+			MetricName:   m.MetricName,
+			ResourceType: m.ResourceType,
+			Stat:         m.Statistic,
+		}
+	}
+
+	labels := make([]CWDashboardBodyWidgetPropertyExplorerLabel, len(w.Labels))
+	for i, l := range w.Labels {
+		labels[i] = CWDashboardBodyWidgetPropertyExplorerLabel{
+			Key:   l.Key,
+			Value: l.Value,
+		}
+	}
+
+	var aggregateBy *CWDashboardBodyWidgetPropertyExplorerAggregateBy
+	if w.AggregateBy != nil {
+		aggregateBy = &CWDashboardBodyWidgetPropertyExplorerAggregateBy{
+			Key:  w.AggregateBy.Key,
+			Func: w.AggregateBy.Function,
+		}
+	}
+
+	widgetOptions := &CWDashboardBodyWidgetPropertyExplorerOptions{
+		RowsPerPage:   w.RowsPerPage,
+		Stacked:       w.Stacked,
+		View:          w.View,
+		WidgetsPerRow: w.WidgetsPerRow,
+	}
+	if w.LegendPosition != "" {
+		widgetOptions.Legend = &CWDashboardBodyWidgetPropertyMetricLegend{
+			Position: w.LegendPosition,
+		}
+	}
+
+	cwWidget := CWDashboardBodyWidget{
+		Type:   "explorer",
+		Width:  w.Width,
+		Height: widgetHeightOrDefault(w.Height, defaultExplorerWidgetHeight),
+		Properties: CWDashboardBodyWidgetPropertyExplorer{
+			AggregateBy:   aggregateBy,
+			Labels:        labels,
+			Metrics:       metrics,
+			Period:        w.Period,
+			Region:        w.Region,
+			SplitBy:       w.SplitBy,
+			Title:         w.Title,
+			WidgetOptions: widgetOptions,
+		},
+	}
+
+	pinWidget(&cwWidget, w.X, w.Y)
+
+	tflog.Debug(ctx, "built explorer widget", map[string]interface{}{
+		"widget": cwWidget,
+	})
+
+	return cwWidget, nil
+}
@@ -0,0 +1,236 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"github.com/tj/assert"
+)
+
+func TestExplorerWidgetDataSourceModel_Validate(t *testing.T) {
+	validMetrics := []explorerWidgetMetricDataSourceModel{
+		{
+			MetricName:   types.StringValue("CPUUtilization"),
+			ResourceType: types.StringValue("AWS::EC2::Instance"),
+			Statistic:    types.StringValue("Average"),
+		},
+	}
+	validLabels := []explorerWidgetLabelDataSourceModel{
+		{
+			Key:   types.StringValue("Service"),
+			Value: types.StringValue("web"),
+		},
+	}
+
+	tests := []struct {
+		name    string
+		model   explorerWidgetDataSourceModel
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "valid complete model",
+			model: explorerWidgetDataSourceModel{
+				Metrics: validMetrics,
+				Labels:  validLabels,
+				AggregateBy: &explorerWidgetAggregateByDataSourceModel{
+					Key:      types.StringValue("InstanceType"),
+					Function: types.StringValue("MAX"),
+				},
+				Period:         types.Int32Value(300),
+				RowsPerPage:    types.Int32Value(50),
+				WidgetsPerRow:  types.Int32Value(2),
+				View:           types.StringValue("bar"),
+				LegendPosition: types.StringValue("bottom"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "no metrics",
+			model: explorerWidgetDataSourceModel{
+				Labels: validLabels,
+			},
+			wantErr: true,
+			errMsg:  "metrics must contain at least one metric",
+		},
+		{
+			name: "invalid statistic",
+			model: explorerWidgetDataSourceModel{
+				Metrics: []explorerWidgetMetricDataSourceModel{
+					{
+						MetricName:   types.StringValue("CPUUtilization"),
+						ResourceType: types.StringValue("AWS::EC2::Instance"),
+						Statistic:    types.StringValue("Median"),
+					},
+				},
+				Labels: validLabels,
+			},
+			wantErr: true,
+			errMsg:  "invalid statistic: Median",
+		},
+		{
+			name: "no labels",
+			model: explorerWidgetDataSourceModel{
+				Metrics: validMetrics,
+			},
+			wantErr: true,
+			errMsg:  "labels must contain at least one tag filter",
+		},
+		{
+			name: "invalid aggregate function",
+			model: explorerWidgetDataSourceModel{
+				Metrics: validMetrics,
+				Labels:  validLabels,
+				AggregateBy: &explorerWidgetAggregateByDataSourceModel{
+					Key:      types.StringValue("InstanceType"),
+					Function: types.StringValue("Average"),
+				},
+			},
+			wantErr: true,
+			errMsg:  "aggregate_by function must be one of 'AVG', 'MIN', 'MAX', or 'SUM', got: Average",
+		},
+		{
+			name: "invalid period",
+			model: explorerWidgetDataSourceModel{
+				Metrics: validMetrics,
+				Labels:  validLabels,
+				Period:  types.Int32Value(90),
+			},
+			wantErr: true,
+			errMsg:  "period must be 60 or a multiple of 60, got: 90",
+		},
+		{
+			name: "invalid rows per page",
+			model: explorerWidgetDataSourceModel{
+				Metrics:     validMetrics,
+				Labels:      validLabels,
+				RowsPerPage: types.Int32Value(0),
+			},
+			wantErr: true,
+			errMsg:  "rows_per_page must be greater than 0, got: 0",
+		},
+		{
+			name: "too many widgets per row",
+			model: explorerWidgetDataSourceModel{
+				Metrics:       validMetrics,
+				Labels:        validLabels,
+				WidgetsPerRow: types.Int32Value(5),
+			},
+			wantErr: true,
+			errMsg:  "widgets_per_row must be between 1 and 4, got: 5",
+		},
+		{
+			name: "invalid view",
+			model: explorerWidgetDataSourceModel{
+				Metrics: validMetrics,
+				Labels:  validLabels,
+				View:    types.StringValue("singleValue"),
+			},
+			wantErr: true,
+			errMsg:  "view must be one of 'timeSeries', 'bar', or 'pie', got: singleValue",
+		},
+		{
+			name: "invalid legend position",
+			model: explorerWidgetDataSourceModel{
+				Metrics:        validMetrics,
+				Labels:         validLabels,
+				LegendPosition: types.StringValue("top"),
+			},
+			wantErr: true,
+			errMsg:  "legend_position must be one of 'right', 'bottom', or 'hidden', got: top",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.model.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Validate() error = nil, want error")
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Validate() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestExplorerWidgetDataSourceSettings_ToCWDashboardBodyWidget(t *testing.T) {
+	input := explorerWidgetDataSourceSettings{
+		Metrics: []explorerWidgetMetricDataSourceSettings{
+			{
+				MetricName:   "CPUUtilization",
+				ResourceType: "AWS::EC2::Instance",
+				Statistic:    "Average",
+			},
+		},
+		Labels: []explorerWidgetLabelDataSourceSettings{
+			{Key: "Service", Value: "web"},
+			{Key: "Team"},
+		},
+		AggregateBy: &explorerWidgetAggregateByDataSourceSettings{
+			Key:      "InstanceType",
+			Function: "MAX",
+		},
+		SplitBy:        "AvailabilityZone",
+		Period:         300,
+		Region:         "us-east-1",
+		Title:          "Web Fleet",
+		RowsPerPage:    50,
+		WidgetsPerRow:  2,
+		View:           "timeSeries",
+		Stacked:        true,
+		LegendPosition: "bottom",
+		Width:          24,
+		Height:         15,
+	}
+
+	actual, err := input.ToCWDashboardBodyWidget(context.Background())
+	require.NoError(t, err)
+
+	assert.Equal(t, CWDashboardBodyWidget{
+		Type:   "explorer",
+		X:      0,
+		Y:      0,
+		Width:  24,
+		Height: 15,
+		Properties: CWDashboardBodyWidgetPropertyExplorer{
+			AggregateBy: &CWDashboardBodyWidgetPropertyExplorerAggregateBy{
+				Key:  "InstanceType",
+				Func: "MAX",
+			},
+			Labels: []CWDashboardBodyWidgetPropertyExplorerLabel{
+				{Key: "Service", Value: "web"},
+				{Key: "Team"},
+			},
+			Metrics: []CWDashboardBodyWidgetPropertyExplorerMetric{
+				{
+					MetricName:   "CPUUtilization",
+					ResourceType: "AWS::EC2::Instance",
+					Stat:         "Average",
+				},
+			},
+			Period:  300,
+			Region:  "us-east-1",
+			SplitBy: "AvailabilityZone",
+			Title:   "Web Fleet",
+			WidgetOptions: &CWDashboardBodyWidgetPropertyExplorerOptions{
+				Legend: &CWDashboardBodyWidgetPropertyMetricLegend{
+					Position: "bottom",
+				},
+				RowsPerPage:   50,
+				Stacked:       true,
+				View:          "timeSeries",
+				WidgetsPerRow: 2,
+			},
+		},
+	}, actual)
+}
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/Code-Hex/synchro/iso8601"
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
 	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -34,16 +35,58 @@ func (d *graphWidgetDataSource) Metadata(_ context.Context, req datasource.Metad
 // Schema defines the schema for the data source.
 func (d *graphWidgetDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
 	resp.Schema = schema.Schema{
+		Description: "The settings are validated when the data source is read, so a configuration with invalid settings, " +
+			"such as a period which is not a multiple of 60, fails with an `invalid settings` error instead of rendering a widget CloudWatch rejects.",
 		Attributes: map[string]schema.Attribute{
+			"enabled": schema.BoolAttribute{
+				Description: "Whether the widget is in the dashboard. Defaults to `true`. The other widgets are laid out as if a disabled widget was not there",
+				Optional:    true,
+			},
 			"height": schema.Int32Attribute{
-				Description: "Height of the widget",
-				Required:    true,
+				Description: "Height of the widget. Defaults to `6`",
+				Optional:    true,
+			},
+			"labels_visible": schema.BoolAttribute{
+				Description: "Whether to show labels on the slices or bars. Only used when `view` is `pie` or `bar`",
+				Optional:    true,
 			},
 			"left": schema.ListAttribute{
 				Description: "Metrics to display on left Y axis",
 				Optional:    true,
 				ElementType: types.StringType,
 			},
+			"left_annotations": schema.ListNestedAttribute{
+				Description: "Horizontal annotations to display on the left Y axis",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"value": schema.Float64Attribute{
+							Description: "The metric value in the graph where the horizontal annotation line is to appear",
+							Required:    true,
+						},
+						"end_value": schema.Float64Attribute{
+							Description: "The end of the band. Required when `fill` is `between`",
+							Optional:    true,
+						},
+						"label": schema.StringAttribute{
+							Description: "A string that appears on the graph next to the annotation",
+							Optional:    true,
+						},
+						"color": schema.StringAttribute{
+							Description: "The hex color code, prefixed with '#' (e.g. '#00ff00'), to be used for the annotation",
+							Optional:    true,
+						},
+						"fill": schema.StringAttribute{
+							Description: "How to use fill with the annotation. Valid Values: `above` | `below` | `between`",
+							Optional:    true,
+						},
+						"visible": schema.BoolAttribute{
+							Description: "Whether the annotation is visible. Defaults to `true`",
+							Optional:    true,
+						},
+					},
+				},
+			},
 			"left_y_axis": schema.SingleNestedAttribute{
 				Description: "Settings for the left Y axis",
 				Optional:    true,
@@ -87,6 +130,38 @@ func (d *graphWidgetDataSource) Schema(_ context.Context, _ datasource.SchemaReq
 				Optional:    true,
 				ElementType: types.StringType,
 			},
+			"right_annotations": schema.ListNestedAttribute{
+				Description: "Horizontal annotations to display on the right Y axis",
+				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"value": schema.Float64Attribute{
+							Description: "The metric value in the graph where the horizontal annotation line is to appear",
+							Required:    true,
+						},
+						"end_value": schema.Float64Attribute{
+							Description: "The end of the band. Required when `fill` is `between`",
+							Optional:    true,
+						},
+						"label": schema.StringAttribute{
+							Description: "A string that appears on the graph next to the annotation",
+							Optional:    true,
+						},
+						"color": schema.StringAttribute{
+							Description: "The hex color code, prefixed with '#' (e.g. '#00ff00'), to be used for the annotation",
+							Optional:    true,
+						},
+						"fill": schema.StringAttribute{
+							Description: "How to use fill with the annotation. Valid Values: `above` | `below` | `between`",
+							Optional:    true,
+						},
+						"visible": schema.BoolAttribute{
+							Description: "Whether the annotation is visible. Defaults to `true`",
+							Optional:    true,
+						},
+					},
+				},
+			},
 			"right_y_axis": schema.SingleNestedAttribute{
 				Description: "Settings for the right Y axis",
 				Optional:    true,
@@ -121,6 +196,29 @@ func (d *graphWidgetDataSource) Schema(_ context.Context, _ datasource.SchemaReq
 				Description: "The default statistic to be displayed for each metric",
 				Optional:    true,
 			},
+			"table": schema.SingleNestedAttribute{
+				Description: "Settings for the table. Only used when `view` is `table`",
+				Optional:    true,
+				Attributes: map[string]schema.Attribute{
+					"layout": schema.StringAttribute{
+						Description: "Whether the time series are shown as rows or columns. Valid Values: `horizontal` | `vertical`",
+						Optional:    true,
+					},
+					"show_time_series_data": schema.BoolAttribute{
+						Description: "Whether to show the data points of each time series. If false, only the summary columns are shown",
+						Optional:    true,
+					},
+					"sticky_summary": schema.BoolAttribute{
+						Description: "Whether the summary columns stay in place when the table is scrolled horizontally",
+						Optional:    true,
+					},
+					"summary_columns": schema.ListAttribute{
+						Description: "The summary columns to show. Valid Values: `MIN` | `MAX` | `SUM` | `AVG`",
+						Optional:    true,
+						ElementType: types.StringType,
+					},
+				},
+			},
 			"timezone": schema.StringAttribute{
 				Description: "The timezone to use for the widget",
 				Optional:    true,
@@ -129,13 +227,54 @@ func (d *graphWidgetDataSource) Schema(_ context.Context, _ datasource.SchemaReq
 				Description: "Title for the graph",
 				Optional:    true,
 			},
-			"view": schema.StringAttribute{
-				Description: "Display this metric",
+			"vertical_annotations": schema.ListNestedAttribute{
+				Description: "Vertical annotations to display on the graph",
 				Optional:    true,
+				NestedObject: schema.NestedAttributeObject{
+					Attributes: map[string]schema.Attribute{
+						"value": schema.StringAttribute{
+							Description: "The date and time in the graph where the vertical annotation line is to appear, in ISO 8601 format",
+							Required:    true,
+						},
+						"end_value": schema.StringAttribute{
+							Description: "The end of the band, in ISO 8601 format. Required when `fill` is `between`",
+							Optional:    true,
+						},
+						"label": schema.StringAttribute{
+							Description: "A string that appears on the graph next to the annotation",
+							Optional:    true,
+						},
+						"color": schema.StringAttribute{
+							Description: "The hex color code, prefixed with '#' (e.g. '#00ff00'), to be used for the annotation",
+							Optional:    true,
+						},
+						"fill": schema.StringAttribute{
+							Description: "How to use fill with the annotation. Valid Values: `before` | `after` | `between`",
+							Optional:    true,
+						},
+						"visible": schema.BoolAttribute{
+							Description: "Whether the annotation is visible. Defaults to `true`",
+							Optional:    true,
+						},
+					},
+				},
+			},
+			"view": schema.StringAttribute{
+				Description: "Display this metric. Valid Values: `timeSeries` | `singleValue` | `table` | `gauge` | `bar` | `pie`. " +
+					"`gauge` requires both `min` and `max` of `left_y_axis` to be set",
+				Optional: true,
 			},
 			"width": schema.Int32Attribute{
-				Description: "Width of the widget, in a grid of 24 units wide",
-				Required:    true,
+				Description: "Width of the widget, in a grid of 24 units wide. Defaults to an even share of its row, see `columns` of `cwdashboard`",
+				Optional:    true,
+			},
+			"x": schema.Int32Attribute{
+				Description: "The horizontal position of the widget, in a grid of 24 units wide. Must be set together with `y`. When omitted, the widget is placed automatically",
+				Optional:    true,
+			},
+			"y": schema.Int32Attribute{
+				Description: "The vertical position of the widget. Must be set together with `x`. When omitted, the widget is placed automatically",
+				Optional:    true,
 			},
 			"json": schema.StringAttribute{
 				Description: "The settings of the widget",
@@ -152,27 +291,67 @@ type graphWidgetYAxisDataSourceModel struct {
 	ShowUnits types.Bool    `tfsdk:"show_units"`
 }
 
+type graphWidgetTableDataSourceModel struct {
+	Layout             types.String   `tfsdk:"layout"`
+	ShowTimeSeriesData types.Bool     `tfsdk:"show_time_series_data"`
+	StickySummary      types.Bool     `tfsdk:"sticky_summary"`
+	SummaryColumns     []types.String `tfsdk:"summary_columns"`
+}
+
+type graphWidgetHorizontalAnnotationDataSourceModel struct {
+	Value    types.Float64 `tfsdk:"value"`
+	EndValue types.Float64 `tfsdk:"end_value"`
+	Label    types.String  `tfsdk:"label"`
+	Color    types.String  `tfsdk:"color"`
+	Fill     types.String  `tfsdk:"fill"`
+	Visible  types.Bool    `tfsdk:"visible"`
+}
+
+type graphWidgetVerticalAnnotationDataSourceModel struct {
+	Value    types.String `tfsdk:"value"`
+	EndValue types.String `tfsdk:"end_value"`
+	Label    types.String `tfsdk:"label"`
+	Color    types.String `tfsdk:"color"`
+	Fill     types.String `tfsdk:"fill"`
+	Visible  types.Bool   `tfsdk:"visible"`
+}
+
 type graphWidgetDataSourceModel struct {
-	Height         types.Int32                      `tfsdk:"height"`
-	Left           []types.String                   `tfsdk:"left"` // JSON string containing array of metrics
-	LeftYAxis      *graphWidgetYAxisDataSourceModel `tfsdk:"left_y_axis"`
-	LegendPosition types.String                     `tfsdk:"legend_position"`
-	LiveData       types.Bool                       `tfsdk:"live_data"`
-	Period         types.Int32                      `tfsdk:"period"`
-	Region         types.String                     `tfsdk:"region"`
-	Right          []types.String                   `tfsdk:"right"` // JSON string containing array of metrics
-	RightYAxis     *graphWidgetYAxisDataSourceModel `tfsdk:"right_y_axis"`
-	Sparkline      types.Bool                       `tfsdk:"sparkline"`
-	Stacked        types.Bool                       `tfsdk:"stacked"`
-	Statistic      types.String                     `tfsdk:"statistic"`
-	Timezone       types.String                     `tfsdk:"timezone"`
-	Title          types.String                     `tfsdk:"title"`
-	View           types.String                     `tfsdk:"view"`
-	Width          types.Int32                      `tfsdk:"width"`
-	Json           types.String                     `tfsdk:"json"`
+	Height              types.Int32                                      `tfsdk:"height"`
+	LabelsVisible       types.Bool                                       `tfsdk:"labels_visible"`
+	Left                []types.String                                   `tfsdk:"left"` // JSON string containing array of metrics
+	LeftAnnotations     []graphWidgetHorizontalAnnotationDataSourceModel `tfsdk:"left_annotations"`
+	LeftYAxis           *graphWidgetYAxisDataSourceModel                 `tfsdk:"left_y_axis"`
+	LegendPosition      types.String                                     `tfsdk:"legend_position"`
+	LiveData            types.Bool                                       `tfsdk:"live_data"`
+	Period              types.Int32                                      `tfsdk:"period"`
+	Region              types.String                                     `tfsdk:"region"`
+	Right               []types.String                                   `tfsdk:"right"` // JSON string containing array of metrics
+	RightAnnotations    []graphWidgetHorizontalAnnotationDataSourceModel `tfsdk:"right_annotations"`
+	RightYAxis          *graphWidgetYAxisDataSourceModel                 `tfsdk:"right_y_axis"`
+	Sparkline           types.Bool                                       `tfsdk:"sparkline"`
+	Stacked             types.Bool                                       `tfsdk:"stacked"`
+	Statistic           types.String                                     `tfsdk:"statistic"`
+	Table               *graphWidgetTableDataSourceModel                 `tfsdk:"table"`
+	Timezone            types.String                                     `tfsdk:"timezone"`
+	Title               types.String                                     `tfsdk:"title"`
+	VerticalAnnotations []graphWidgetVerticalAnnotationDataSourceModel   `tfsdk:"vertical_annotations"`
+	View                types.String                                     `tfsdk:"view"`
+	Width               types.Int32                                      `tfsdk:"width"`
+	X                   types.Int32                                      `tfsdk:"x"`
+	Y                   types.Int32                                      `tfsdk:"y"`
+	Enabled             types.Bool                                       `tfsdk:"enabled"`
+	Json                types.String                                     `tfsdk:"json"`
 }
 
 func (d *graphWidgetDataSourceModel) Validate() error {
+	if err := validateWidgetSize(d.Width, d.Height); err != nil {
+		return err
+	}
+	if err := validateWidgetPosition(d.X, d.Y); err != nil {
+		return err
+	}
+
 	// Period must be 60 or a multiple of 60
 	if !d.Period.IsNull() {
 		if period := d.Period.ValueInt32(); period < 60 || period%60 != 0 {
@@ -243,11 +422,145 @@ func (d *graphWidgetDataSourceModel) Validate() error {
 	if !d.View.IsNull() {
 		view := d.View.ValueString()
 		validViews := map[string]bool{
-			"timeSeries":  true,
-			"singleValue": true,
+			graphWidgetViewTimeSeries:  true,
+			graphWidgetViewSingleValue: true,
+			graphWidgetViewTable:       true,
+			graphWidgetViewGauge:       true,
+			graphWidgetViewBar:         true,
+			graphWidgetViewPie:         true,
 		}
 		if !validViews[view] {
-			return fmt.Errorf("view must be either 'timeSeries' or 'singleValue', got: %s", view)
+			return fmt.Errorf("view must be one of 'timeSeries', 'singleValue', 'table', 'gauge', 'bar', or 'pie', got: %s", view)
+		}
+	}
+
+	view := d.View.ValueString()
+
+	// Gauge needs a range to draw the dial
+	if view == graphWidgetViewGauge {
+		if d.LeftYAxis == nil || d.LeftYAxis.Min.IsNull() || d.LeftYAxis.Max.IsNull() {
+			return fmt.Errorf("left_y_axis min and max must be set when view is 'gauge'")
+		}
+		if d.LeftYAxis.Min.ValueFloat64() >= d.LeftYAxis.Max.ValueFloat64() {
+			return fmt.Errorf("left_y_axis min must be less than max when view is 'gauge'")
+		}
+	}
+
+	if !d.LabelsVisible.IsNull() && view != graphWidgetViewPie && view != graphWidgetViewBar {
+		return fmt.Errorf("labels_visible can only be set when view is 'pie' or 'bar'")
+	}
+
+	if d.Stacked.ValueBool() && (view == graphWidgetViewGauge || view == graphWidgetViewBar || view == graphWidgetViewPie) {
+		return fmt.Errorf("stacked cannot be set when view is '%s'", view)
+	}
+
+	// Validate Table
+	if d.Table != nil {
+		if view != graphWidgetViewTable {
+			return fmt.Errorf("table can only be set when view is 'table'")
+		}
+
+		if !d.Table.Layout.IsNull() {
+			layout := d.Table.Layout.ValueString()
+			if layout != "horizontal" && layout != "vertical" {
+				return fmt.Errorf("table layout must be either 'horizontal' or 'vertical', got: %s", layout)
+			}
+		}
+
+		validSummaryColumns := map[string]bool{
+			"MIN": true,
+			"MAX": true,
+			"SUM": true,
+			"AVG": true,
+		}
+		seen := make(map[string]bool)
+		for _, c := range d.Table.SummaryColumns {
+			column := c.ValueString()
+			if !validSummaryColumns[column] {
+				return fmt.Errorf("table summary_columns must only contain 'MIN', 'MAX', 'SUM', or 'AVG', got: %s", column)
+			}
+			if seen[column] {
+				return fmt.Errorf("table summary_columns must not contain duplicates, got: %s", column)
+			}
+			seen[column] = true
+		}
+	}
+
+	// Validate annotations
+	if err := validateHorizontalAnnotations("left_annotations", d.LeftAnnotations); err != nil {
+		return err
+	}
+	if err := validateHorizontalAnnotations("right_annotations", d.RightAnnotations); err != nil {
+		return err
+	}
+	if err := validateVerticalAnnotations(d.VerticalAnnotations); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+var (
+	annotationColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+)
+
+func validateHorizontalAnnotations(name string, annotations []graphWidgetHorizontalAnnotationDataSourceModel) error {
+	validFills := map[string]bool{
+		"above":   true,
+		"below":   true,
+		"between": true,
+	}
+
+	for i, a := range annotations {
+		if color := a.Color.ValueString(); color != "" && !annotationColorPattern.MatchString(color) {
+			return fmt.Errorf("%s[%d]: invalid color format: %s, must be a six-digit hex color code (e.g., #FF0000)", name, i, color)
+		}
+
+		fill := a.Fill.ValueString()
+		if !a.Fill.IsNull() && !validFills[fill] {
+			return fmt.Errorf("%s[%d]: fill must be one of 'above', 'below', or 'between', got: %s", name, i, fill)
+		}
+		if fill == "between" && a.EndValue.IsNull() {
+			return fmt.Errorf("%s[%d]: end_value is required when fill is 'between'", name, i)
+		}
+		if fill != "between" && !a.EndValue.IsNull() {
+			return fmt.Errorf("%s[%d]: end_value can only be set when fill is 'between'", name, i)
+		}
+	}
+
+	return nil
+}
+
+func validateVerticalAnnotations(annotations []graphWidgetVerticalAnnotationDataSourceModel) error {
+	validFills := map[string]bool{
+		"before":  true,
+		"after":   true,
+		"between": true,
+	}
+
+	for i, a := range annotations {
+		if _, err := iso8601.ParseDateTime(a.Value.ValueString()); err != nil {
+			return fmt.Errorf("vertical_annotations[%d]: value must be a valid ISO8601 date: %w", i, err)
+		}
+
+		if color := a.Color.ValueString(); color != "" && !annotationColorPattern.MatchString(color) {
+			return fmt.Errorf("vertical_annotations[%d]: invalid color format: %s, must be a six-digit hex color code (e.g., #FF0000)", i, color)
+		}
+
+		fill := a.Fill.ValueString()
+		if !a.Fill.IsNull() && !validFills[fill] {
+			return fmt.Errorf("vertical_annotations[%d]: fill must be one of 'before', 'after', or 'between', got: %s", i, fill)
+		}
+		if fill == "between" && a.EndValue.IsNull() {
+			return fmt.Errorf("vertical_annotations[%d]: end_value is required when fill is 'between'", i)
+		}
+		if fill != "between" && !a.EndValue.IsNull() {
+			return fmt.Errorf("vertical_annotations[%d]: end_value can only be set when fill is 'between'", i)
+		}
+		if !a.EndValue.IsNull() {
+			if _, err := iso8601.ParseDateTime(a.EndValue.ValueString()); err != nil {
+				return fmt.Errorf("vertical_annotations[%d]: end_value must be a valid ISO8601 date: %w", i, err)
+			}
 		}
 	}
 
@@ -255,53 +568,103 @@ func (d *graphWidgetDataSourceModel) Validate() error {
 }
 
 type graphWidgetYAxisDataSourceSettings struct {
-	Label     string  `json:"label,omitempty"`
-	Max       float64 `json:"max,omitempty"`
-	Min       float64 `json:"min,omitempty"`
-	ShowUnits bool    `json:"show_units,omitempty"`
+	Label     string   `json:"label,omitempty"`
+	Max       *float64 `json:"max,omitempty"`
+	Min       *float64 `json:"min,omitempty"`
+	ShowUnits bool     `json:"show_units,omitempty"`
+}
+
+type graphWidgetTableDataSourceSettings struct {
+	Layout             string   `json:"layout,omitempty"`
+	ShowTimeSeriesData *bool    `json:"show_time_series_data,omitempty"`
+	StickySummary      bool     `json:"sticky_summary,omitempty"`
+	SummaryColumns     []string `json:"summary_columns,omitempty"`
+}
+
+type graphWidgetHorizontalAnnotationDataSourceSettings struct {
+	Value    float64  `json:"value"`
+	EndValue *float64 `json:"end_value,omitempty"`
+	Label    string   `json:"label,omitempty"`
+	Color    string   `json:"color,omitempty"`
+	Fill     string   `json:"fill,omitempty"`
+	Visible  *bool    `json:"visible,omitempty"`
+}
+
+type graphWidgetVerticalAnnotationDataSourceSettings struct {
+	Value    string `json:"value"`
+	EndValue string `json:"end_value,omitempty"`
+	Label    string `json:"label,omitempty"`
+	Color    string `json:"color,omitempty"`
+	Fill     string `json:"fill,omitempty"`
+	Visible  *bool  `json:"visible,omitempty"`
 }
 
 const (
 	typeGraphWidget = "graph"
+
+	defaultGraphWidgetHeight = 6
+
+	graphWidgetViewTimeSeries  = "timeSeries"
+	graphWidgetViewSingleValue = "singleValue"
+	graphWidgetViewTable       = "table"
+	graphWidgetViewGauge       = "gauge"
+	graphWidgetViewBar         = "bar"
+	graphWidgetViewPie         = "pie"
 )
 
 type graphWidgetDataSourceSettings struct {
-	Type           string                              `json:"type"`
-	Height         int32                               `json:"height"`
-	Left           []IMetricSettings                   `json:"left,omitempty"`
-	LeftYAxis      *graphWidgetYAxisDataSourceSettings `json:"left_y_axis,omitempty"`
-	LegendPosition string                              `json:"legend_position,omitempty"`
-	LiveData       bool                                `json:"live_data,omitempty"`
-	Period         int32                               `json:"period,omitempty"`
-	Region         string                              `json:"region,omitempty"`
-	Right          []IMetricSettings                   `json:"right,omitempty"`
-	RightYAxis     *graphWidgetYAxisDataSourceSettings `json:"right_y_axis,omitempty"`
-	Sparkline      bool                                `json:"sparkline,omitempty"`
-	Stacked        bool                                `json:"stacked,omitempty"`
-	Statistic      string                              `json:"statistic,omitempty"`
-	Timezone       string                              `json:"timezone,omitempty"`
-	Title          string                              `json:"title,omitempty"`
-	View           string                              `json:"view,omitempty"`
-	Width          int32                               `json:"width"`
+	Type                string                                              `json:"type"`
+	Height              int32                                               `json:"height"`
+	LabelsVisible       *bool                                               `json:"labels_visible,omitempty"`
+	Left                []IMetricSettings                                   `json:"left,omitempty"`
+	LeftAnnotations     []graphWidgetHorizontalAnnotationDataSourceSettings `json:"left_annotations,omitempty"`
+	LeftYAxis           *graphWidgetYAxisDataSourceSettings                 `json:"left_y_axis,omitempty"`
+	LegendPosition      string                                              `json:"legend_position,omitempty"`
+	LiveData            bool                                                `json:"live_data,omitempty"`
+	Period              int32                                               `json:"period,omitempty"`
+	Region              string                                              `json:"region,omitempty"`
+	Right               []IMetricSettings                                   `json:"right,omitempty"`
+	RightAnnotations    []graphWidgetHorizontalAnnotationDataSourceSettings `json:"right_annotations,omitempty"`
+	RightYAxis          *graphWidgetYAxisDataSourceSettings                 `json:"right_y_axis,omitempty"`
+	Sparkline           bool                                                `json:"sparkline,omitempty"`
+	Stacked             bool                                                `json:"stacked,omitempty"`
+	Statistic           string                                              `json:"statistic,omitempty"`
+	Table               *graphWidgetTableDataSourceSettings                 `json:"table,omitempty"`
+	Timezone            string                                              `json:"timezone,omitempty"`
+	Title               string                                              `json:"title,omitempty"`
+	VerticalAnnotations []graphWidgetVerticalAnnotationDataSourceSettings   `json:"vertical_annotations,omitempty"`
+	View                string                                              `json:"view,omitempty"`
+	Width               int32                                               `json:"width"`
+	X                   *int32                                              `json:"x,omitempty"`
+	Y                   *int32                                              `json:"y,omitempty"`
+	Enabled             *bool                                               `json:"enabled,omitempty"`
 }
 
 func (s *graphWidgetDataSourceSettings) UnmarshalJSON(data []byte) error {
 	var intermediate struct {
-		Type           string                              `json:"type"`
-		Height         int32                               `json:"height"`
-		LeftYAxis      *graphWidgetYAxisDataSourceSettings `json:"left_y_axis,omitempty"`
-		LegendPosition string                              `json:"legend_position,omitempty"`
-		LiveData       bool                                `json:"live_data,omitempty"`
-		Period         int32                               `json:"period,omitempty"`
-		Region         string                              `json:"region,omitempty"`
-		RightYAxis     *graphWidgetYAxisDataSourceSettings `json:"right_y_axis,omitempty"`
-		Sparkline      bool                                `json:"sparkline,omitempty"`
-		Stacked        bool                                `json:"stacked,omitempty"`
-		Statistic      string                              `json:"statistic,omitempty"`
-		Timezone       string                              `json:"timezone,omitempty"`
-		Title          string                              `json:"title,omitempty"`
-		View           string                              `json:"view,omitempty"`
-		Width          int32                               `json:"width"`
+		Type                string                                              `json:"type"`
+		Height              int32                                               `json:"height"`
+		LabelsVisible       *bool                                               `json:"labels_visible,omitempty"`
+		LeftAnnotations     []graphWidgetHorizontalAnnotationDataSourceSettings `json:"left_annotations,omitempty"`
+		LeftYAxis           *graphWidgetYAxisDataSourceSettings                 `json:"left_y_axis,omitempty"`
+		LegendPosition      string                                              `json:"legend_position,omitempty"`
+		LiveData            bool                                                `json:"live_data,omitempty"`
+		Period              int32                                               `json:"period,omitempty"`
+		Region              string                                              `json:"region,omitempty"`
+		RightAnnotations    []graphWidgetHorizontalAnnotationDataSourceSettings `json:"right_annotations,omitempty"`
+		RightYAxis          *graphWidgetYAxisDataSourceSettings                 `json:"right_y_axis,omitempty"`
+		Sparkline           bool                                                `json:"sparkline,omitempty"`
+		Stacked             bool                                                `json:"stacked,omitempty"`
+		Statistic           string                                              `json:"statistic,omitempty"`
+		Table               *graphWidgetTableDataSourceSettings                 `json:"table,omitempty"`
+		Timezone            string                                              `json:"timezone,omitempty"`
+		Title               string                                              `json:"title,omitempty"`
+		VerticalAnnotations []graphWidgetVerticalAnnotationDataSourceSettings   `json:"vertical_annotations,omitempty"`
+		View                string                                              `json:"view,omitempty"`
+		Width               int32                                               `json:"width"`
+		X                   *int32                                              `json:"x,omitempty"`
+		Y                   *int32                                              `json:"y,omitempty"`
+		Enabled             *bool                                               `json:"enabled,omitempty"`
 		// Left/Right has multiple types, so we need to unmarshal them separately
 		Left  []interface{} `json:"left"`
 		Right []interface{} `json:"right"`
@@ -313,19 +676,27 @@ func (s *graphWidgetDataSourceSettings) UnmarshalJSON(data []byte) error {
 
 	s.Type = intermediate.Type
 	s.Height = intermediate.Height
+	s.LabelsVisible = intermediate.LabelsVisible
+	s.LeftAnnotations = intermediate.LeftAnnotations
 	s.LeftYAxis = intermediate.LeftYAxis
 	s.LegendPosition = intermediate.LegendPosition
 	s.LiveData = intermediate.LiveData
 	s.Period = intermediate.Period
 	s.Region = intermediate.Region
+	s.RightAnnotations = intermediate.RightAnnotations
 	s.RightYAxis = intermediate.RightYAxis
 	s.Sparkline = intermediate.Sparkline
 	s.Stacked = intermediate.Stacked
 	s.Statistic = intermediate.Statistic
+	s.Table = intermediate.Table
 	s.Timezone = intermediate.Timezone
 	s.Title = intermediate.Title
+	s.VerticalAnnotations = intermediate.VerticalAnnotations
 	s.View = intermediate.View
 	s.Width = intermediate.Width
+	s.X = intermediate.X
+	s.Y = intermediate.Y
+	s.Enabled = intermediate.Enabled
 
 	// Process left and right metrics separately
 	left, err := processMetrics(intermediate.Left)
@@ -398,6 +769,11 @@ func (d *graphWidgetDataSource) Read(ctx context.Context, req datasource.ReadReq
 		return
 	}
 
+	if err := state.Validate(); err != nil {
+		resp.Diagnostics.AddError("invalid settings", err.Error())
+		return
+	}
+
 	// Parse left metrics from JSON
 	leftMetrics := make([]IMetricSettings, len(state.Left))
 	for i, metricJson := range state.Left {
@@ -441,36 +817,55 @@ func (d *graphWidgetDataSource) Read(ctx context.Context, req datasource.ReadReq
 	}
 
 	settings := graphWidgetDataSourceSettings{
-		Type:           typeGraphWidget,
-		Height:         state.Height.ValueInt32(),
-		Left:           leftMetrics,
-		LegendPosition: state.LegendPosition.ValueString(),
-		LiveData:       state.LiveData.ValueBool(),
-		Period:         state.Period.ValueInt32(),
-		Region:         state.Region.ValueString(),
-		Right:          rightMetrics,
-		Sparkline:      state.Sparkline.ValueBool(),
-		Stacked:        state.Stacked.ValueBool(),
-		Statistic:      state.Statistic.ValueString(),
-		Timezone:       state.Timezone.ValueString(),
-		Title:          state.Title.ValueString(),
-		View:           state.View.ValueString(),
-		Width:          state.Width.ValueInt32(),
+		Type:                typeGraphWidget,
+		Height:              state.Height.ValueInt32(),
+		LabelsVisible:       state.LabelsVisible.ValueBoolPointer(),
+		Left:                leftMetrics,
+		LeftAnnotations:     toHorizontalAnnotationSettings(state.LeftAnnotations),
+		LegendPosition:      state.LegendPosition.ValueString(),
+		LiveData:            state.LiveData.ValueBool(),
+		Period:              state.Period.ValueInt32(),
+		Region:              state.Region.ValueString(),
+		Right:               rightMetrics,
+		RightAnnotations:    toHorizontalAnnotationSettings(state.RightAnnotations),
+		Sparkline:           state.Sparkline.ValueBool(),
+		Stacked:             state.Stacked.ValueBool(),
+		Statistic:           state.Statistic.ValueString(),
+		Timezone:            state.Timezone.ValueString(),
+		Title:               state.Title.ValueString(),
+		VerticalAnnotations: toVerticalAnnotationSettings(state.VerticalAnnotations),
+		View:                state.View.ValueString(),
+		Width:               state.Width.ValueInt32(),
+		X:                   state.X.ValueInt32Pointer(),
+		Y:                   state.Y.ValueInt32Pointer(),
+		Enabled:             state.Enabled.ValueBoolPointer(),
 	}
 
 	if state.LeftYAxis != nil {
 		settings.LeftYAxis = &graphWidgetYAxisDataSourceSettings{
 			Label:     state.LeftYAxis.Label.ValueString(),
-			Max:       state.LeftYAxis.Max.ValueFloat64(),
-			Min:       state.LeftYAxis.Min.ValueFloat64(),
+			Max:       state.LeftYAxis.Max.ValueFloat64Pointer(),
+			Min:       state.LeftYAxis.Min.ValueFloat64Pointer(),
 			ShowUnits: state.LeftYAxis.ShowUnits.ValueBool(),
 		}
 	}
+	if state.Table != nil {
+		summaryColumns := make([]string, len(state.Table.SummaryColumns))
+		for i, c := range state.Table.SummaryColumns {
+			summaryColumns[i] = c.ValueString()
+		}
+		settings.Table = &graphWidgetTableDataSourceSettings{
+			Layout:             state.Table.Layout.ValueString(),
+			ShowTimeSeriesData: state.Table.ShowTimeSeriesData.ValueBoolPointer(),
+			StickySummary:      state.Table.StickySummary.ValueBool(),
+			SummaryColumns:     summaryColumns,
+		}
+	}
 	if state.RightYAxis != nil {
 		settings.RightYAxis = &graphWidgetYAxisDataSourceSettings{
 			Label:     state.RightYAxis.Label.ValueString(),
-			Max:       state.RightYAxis.Max.ValueFloat64(),
-			Min:       state.RightYAxis.Min.ValueFloat64(),
+			Max:       state.RightYAxis.Max.ValueFloat64Pointer(),
+			Min:       state.RightYAxis.Min.ValueFloat64Pointer(),
 			ShowUnits: state.RightYAxis.ShowUnits.ValueBool(),
 		}
 	}
@@ -494,7 +889,100 @@ func (d *graphWidgetDataSource) Read(ctx context.Context, req datasource.ReadReq
 	}
 }
 
-func (w graphWidgetDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Context, beforeWidgetPosition *widgetPosition) (CWDashboardBodyWidget, error) {
+func toHorizontalAnnotationSettings(models []graphWidgetHorizontalAnnotationDataSourceModel) []graphWidgetHorizontalAnnotationDataSourceSettings {
+	var settings []graphWidgetHorizontalAnnotationDataSourceSettings
+	for _, m := range models {
+		s := graphWidgetHorizontalAnnotationDataSourceSettings{
+			Value: m.Value.ValueFloat64(),
+			Label: m.Label.ValueString(),
+			Color: m.Color.ValueString(),
+			Fill:  m.Fill.ValueString(),
+		}
+		if !m.EndValue.IsNull() {
+			s.EndValue = m.EndValue.ValueFloat64Pointer()
+		}
+		if !m.Visible.IsNull() {
+			s.Visible = m.Visible.ValueBoolPointer()
+		}
+		settings = append(settings, s)
+	}
+
+	return settings
+}
+
+func toVerticalAnnotationSettings(models []graphWidgetVerticalAnnotationDataSourceModel) []graphWidgetVerticalAnnotationDataSourceSettings {
+	var settings []graphWidgetVerticalAnnotationDataSourceSettings
+	for _, m := range models {
+		s := graphWidgetVerticalAnnotationDataSourceSettings{
+			Value:    m.Value.ValueString(),
+			EndValue: m.EndValue.ValueString(),
+			Label:    m.Label.ValueString(),
+			Color:    m.Color.ValueString(),
+			Fill:     m.Fill.ValueString(),
+		}
+		if !m.Visible.IsNull() {
+			s.Visible = m.Visible.ValueBoolPointer()
+		}
+		settings = append(settings, s)
+	}
+
+	return settings
+}
+
+func (w graphWidgetDataSourceSettings) buildAnnotations() *CWDashboardBodyWidgetPropertyMetricAnnotations {
+	horizontal := make([]CWDashboardBodyWidgetPropertyMetricAnnotationsHorizontal, 0)
+	for _, side := range []struct {
+		yAxis       string
+		annotations []graphWidgetHorizontalAnnotationDataSourceSettings
+	}{
+		{yAxis: "left", annotations: w.LeftAnnotations},
+		{yAxis: "right", annotations: w.RightAnnotations},
+	} {
+		for _, a := range side.annotations {
+			annotation := CWDashboardBodyWidgetPropertyMetricAnnotationsHorizontal{
+				Value:   a.Value,
+				Label:   a.Label,
+				Color:   a.Color,
+				Fill:    a.Fill,
+				Visible: a.Visible,
+				YAxis:   side.yAxis,
+			}
+			// NOTE: "between" is not a CloudWatch fill value, it is expressed as a band instead
+			if a.Fill == "between" {
+				annotation.Fill = ""
+				annotation.EndValue = a.EndValue
+			}
+			horizontal = append(horizontal, annotation)
+		}
+	}
+
+	vertical := make([]CWDashboardBodyWidgetPropertyMetricAnnotationsVertical, 0)
+	for _, a := range w.VerticalAnnotations {
+		annotation := CWDashboardBodyWidgetPropertyMetricAnnotationsVertical{
+			Value:   a.Value,
+			Label:   a.Label,
+			Color:   a.Color,
+			Fill:    a.Fill,
+			Visible: a.Visible,
+		}
+		if a.Fill == "between" {
+			annotation.Fill = ""
+			annotation.EndValue = a.EndValue
+		}
+		vertical = append(vertical, annotation)
+	}
+
+	if len(horizontal) == 0 && len(vertical) == 0 {
+		return nil
+	}
+
+	return &CWDashboardBodyWidgetPropertyMetricAnnotations{
+		Horizontal: horizontal,
+		Vertical:   vertical,
+	}
+}
+
+func (w graphWidgetDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Context) (CWDashboardBodyWidget, error) {
 	var leftYAxis *CWDashboardBodyWidgetPropertyMetricYAxisSide
 	if w.LeftYAxis != nil {
 		leftYAxis = &CWDashboardBodyWidgetPropertyMetricYAxisSide{
@@ -523,6 +1011,12 @@ func (w graphWidgetDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Conte
 		}
 	}
 
+	// the ids in the expressions are made unique in the widget, keeping the ids chosen by the user
+	ns := newMetricIdNamespace()
+	if err := reserveMetricIds(ns, w.Left, w.Right); err != nil {
+		return CWDashboardBodyWidget{}, err
+	}
+
 	metrics := make([][]interface{}, 0)
 	for _, metric := range w.Left {
 		switch m := metric.(type) {
@@ -533,7 +1027,7 @@ func (w graphWidgetDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Conte
 			}
 			metrics = append(metrics, settings)
 		case *metricExpressionDataSourceSettings:
-			settingsList, err := m.buildMetricWidgetMetricSettingsList(true)
+			settingsList, err := m.buildMetricWidgetMetricSettingsList(true, ns)
 			if err != nil {
 				return CWDashboardBodyWidget{}, fmt.Errorf("failed to build metric settings: %w", err)
 			}
@@ -553,7 +1047,7 @@ func (w graphWidgetDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Conte
 			}
 			metrics = append(metrics, settings)
 		case *metricExpressionDataSourceSettings:
-			settingsList, err := m.buildMetricWidgetMetricSettingsList(false)
+			settingsList, err := m.buildMetricWidgetMetricSettingsList(false, ns)
 			if err != nil {
 				return CWDashboardBodyWidget{}, fmt.Errorf("failed to build metric settings: %w", err)
 			}
@@ -563,12 +1057,35 @@ func (w graphWidgetDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Conte
 		}
 	}
 
+	if err := validateMetricIds(metrics); err != nil {
+		return CWDashboardBodyWidget{}, err
+	}
+
+	var table *CWDashboardBodyWidgetPropertyMetricTable
+	if w.Table != nil {
+		table = &CWDashboardBodyWidgetPropertyMetricTable{
+			Layout:             w.Table.Layout,
+			StickySummary:      w.Table.StickySummary,
+			ShowTimeSeriesData: w.Table.ShowTimeSeriesData,
+			SummaryColumns:     w.Table.SummaryColumns,
+		}
+	}
+
+	var labels *CWDashboardBodyWidgetPropertyMetricLabels
+	if w.LabelsVisible != nil {
+		labels = &CWDashboardBodyWidgetPropertyMetricLabels{
+			Visible: *w.LabelsVisible,
+		}
+	}
+
 	cwWidget := CWDashboardBodyWidget{
 		Type:   "metric",
 		Width:  w.Width,
-		Height: w.Height,
+		Height: widgetHeightOrDefault(w.Height, defaultGraphWidgetHeight),
 		Properties: CWDashboardBodyWidgetPropertyMetric{
-			LiveData: w.LiveData,
+			Annotations: w.buildAnnotations(),
+			Labels:      labels,
+			LiveData:    w.LiveData,
 			Legend: &CWDashboardBodyWidgetPropertyMetricLegend{
 				Position: w.LegendPosition,
 			},
@@ -582,14 +1099,11 @@ func (w graphWidgetDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Conte
 			Sparkline: w.Sparkline,
 			Timezone:  w.Timezone,
 			YAxis:     yAxis,
-			// NOTE: unnecessary to set because it's not used in the graph widget
-			Table: nil,
+			Table:     table,
 		},
 	}
 
-	position := calculatePosition(widgetSize{Width: cwWidget.Width, Height: cwWidget.Height}, beforeWidgetPosition)
-	cwWidget.X = position.X
-	cwWidget.Y = position.Y
+	pinWidget(&cwWidget, w.X, w.Y)
 
 	tflog.Debug(ctx, "built graph widget", map[string]interface{}{
 		"widget": cwWidget,
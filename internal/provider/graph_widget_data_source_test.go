@@ -2,6 +2,7 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -33,6 +34,24 @@ func TestGraphWidgetDataSourceModel_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "invalid width - wider than the dashboard",
+			model: graphWidgetDataSourceModel{
+				Period: types.Int32Value(60),
+				Width:  types.Int32Value(25),
+			},
+			wantErr: true,
+			errMsg:  "width must be between 1 and 24, got: 25",
+		},
+		{
+			name: "invalid height - zero",
+			model: graphWidgetDataSourceModel{
+				Period: types.Int32Value(60),
+				Height: types.Int32Value(0),
+			},
+			wantErr: true,
+			errMsg:  "height must be greater than 0, got: 0",
+		},
 		{
 			name: "invalid period - not allowed value",
 			model: graphWidgetDataSourceModel{
@@ -110,7 +129,213 @@ func TestGraphWidgetDataSourceModel_Validate(t *testing.T) {
 				View:   types.StringValue("invalid"),
 			},
 			wantErr: true,
-			errMsg:  "view must be either 'timeSeries' or 'singleValue', got: invalid",
+			errMsg:  "view must be one of 'timeSeries', 'singleValue', 'table', 'gauge', 'bar', or 'pie', got: invalid",
+		},
+		{
+			name: "valid gauge view",
+			model: graphWidgetDataSourceModel{
+				View: types.StringValue("gauge"),
+				LeftYAxis: &graphWidgetYAxisDataSourceModel{
+					Min: types.Float64Value(0),
+					Max: types.Float64Value(100),
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "gauge view without range",
+			model: graphWidgetDataSourceModel{
+				View: types.StringValue("gauge"),
+				LeftYAxis: &graphWidgetYAxisDataSourceModel{
+					Max: types.Float64Value(100),
+				},
+			},
+			wantErr: true,
+			errMsg:  "left_y_axis min and max must be set when view is 'gauge'",
+		},
+		{
+			name: "gauge view with inverted range",
+			model: graphWidgetDataSourceModel{
+				View: types.StringValue("gauge"),
+				LeftYAxis: &graphWidgetYAxisDataSourceModel{
+					Min: types.Float64Value(100),
+					Max: types.Float64Value(0),
+				},
+			},
+			wantErr: true,
+			errMsg:  "left_y_axis min must be less than max when view is 'gauge'",
+		},
+		{
+			name: "valid pie view with labels",
+			model: graphWidgetDataSourceModel{
+				View:           types.StringValue("pie"),
+				LabelsVisible:  types.BoolValue(true),
+				LegendPosition: types.StringValue("hidden"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "labels on time series view",
+			model: graphWidgetDataSourceModel{
+				View:          types.StringValue("timeSeries"),
+				LabelsVisible: types.BoolValue(true),
+			},
+			wantErr: true,
+			errMsg:  "labels_visible can only be set when view is 'pie' or 'bar'",
+		},
+		{
+			name: "stacked bar view",
+			model: graphWidgetDataSourceModel{
+				View:    types.StringValue("bar"),
+				Stacked: types.BoolValue(true),
+			},
+			wantErr: true,
+			errMsg:  "stacked cannot be set when view is 'bar'",
+		},
+		{
+			name: "valid table view",
+			model: graphWidgetDataSourceModel{
+				View: types.StringValue("table"),
+				Table: &graphWidgetTableDataSourceModel{
+					Layout:         types.StringValue("vertical"),
+					StickySummary:  types.BoolValue(true),
+					SummaryColumns: []types.String{types.StringValue("MIN"), types.StringValue("AVG")},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "table settings without table view",
+			model: graphWidgetDataSourceModel{
+				View:  types.StringValue("timeSeries"),
+				Table: &graphWidgetTableDataSourceModel{},
+			},
+			wantErr: true,
+			errMsg:  "table can only be set when view is 'table'",
+		},
+		{
+			name: "invalid table layout",
+			model: graphWidgetDataSourceModel{
+				View: types.StringValue("table"),
+				Table: &graphWidgetTableDataSourceModel{
+					Layout: types.StringValue("grid"),
+				},
+			},
+			wantErr: true,
+			errMsg:  "table layout must be either 'horizontal' or 'vertical', got: grid",
+		},
+		{
+			name: "invalid table summary column",
+			model: graphWidgetDataSourceModel{
+				View: types.StringValue("table"),
+				Table: &graphWidgetTableDataSourceModel{
+					SummaryColumns: []types.String{types.StringValue("Average")},
+				},
+			},
+			wantErr: true,
+			errMsg:  "table summary_columns must only contain 'MIN', 'MAX', 'SUM', or 'AVG', got: Average",
+		},
+		{
+			name: "duplicate table summary column",
+			model: graphWidgetDataSourceModel{
+				View: types.StringValue("table"),
+				Table: &graphWidgetTableDataSourceModel{
+					SummaryColumns: []types.String{types.StringValue("MAX"), types.StringValue("MAX")},
+				},
+			},
+			wantErr: true,
+			errMsg:  "table summary_columns must not contain duplicates, got: MAX",
+		},
+		{
+			name: "valid annotations",
+			model: graphWidgetDataSourceModel{
+				LeftAnnotations: []graphWidgetHorizontalAnnotationDataSourceModel{
+					{
+						Value: types.Float64Value(100),
+						Color: types.StringValue("#ff0000"),
+						Fill:  types.StringValue("above"),
+					},
+				},
+				RightAnnotations: []graphWidgetHorizontalAnnotationDataSourceModel{
+					{
+						Value:    types.Float64Value(10),
+						EndValue: types.Float64Value(20),
+						Fill:     types.StringValue("between"),
+					},
+				},
+				VerticalAnnotations: []graphWidgetVerticalAnnotationDataSourceModel{
+					{
+						Value: types.StringValue("2024-01-01T00:00:00Z"),
+						Fill:  types.StringValue("after"),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid horizontal annotation fill",
+			model: graphWidgetDataSourceModel{
+				LeftAnnotations: []graphWidgetHorizontalAnnotationDataSourceModel{
+					{
+						Value: types.Float64Value(100),
+						Fill:  types.StringValue("after"),
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "left_annotations[0]: fill must be one of 'above', 'below', or 'between', got: after",
+		},
+		{
+			name: "horizontal band without end value",
+			model: graphWidgetDataSourceModel{
+				RightAnnotations: []graphWidgetHorizontalAnnotationDataSourceModel{
+					{
+						Value: types.Float64Value(100),
+						Fill:  types.StringValue("between"),
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "right_annotations[0]: end_value is required when fill is 'between'",
+		},
+		{
+			name: "invalid horizontal annotation color",
+			model: graphWidgetDataSourceModel{
+				LeftAnnotations: []graphWidgetHorizontalAnnotationDataSourceModel{
+					{
+						Value: types.Float64Value(100),
+						Color: types.StringValue("red"),
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "left_annotations[0]: invalid color format: red, must be a six-digit hex color code (e.g., #FF0000)",
+		},
+		{
+			name: "end value without between fill",
+			model: graphWidgetDataSourceModel{
+				VerticalAnnotations: []graphWidgetVerticalAnnotationDataSourceModel{
+					{
+						Value:    types.StringValue("2024-01-01T00:00:00Z"),
+						EndValue: types.StringValue("2024-01-01T01:00:00Z"),
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "vertical_annotations[0]: end_value can only be set when fill is 'between'",
+		},
+		{
+			name: "invalid vertical annotation fill",
+			model: graphWidgetDataSourceModel{
+				VerticalAnnotations: []graphWidgetVerticalAnnotationDataSourceModel{
+					{
+						Value: types.StringValue("2024-01-01T00:00:00Z"),
+						Fill:  types.StringValue("above"),
+					},
+				},
+			},
+			wantErr: true,
+			errMsg:  "vertical_annotations[0]: fill must be one of 'before', 'after', or 'between', got: above",
 		},
 	}
 
@@ -156,8 +381,8 @@ func TestGraphWidgetDatasourceSettings_ToCWDashboardBodyWidget(t *testing.T) {
 			},
 			LeftYAxis: &graphWidgetYAxisDataSourceSettings{
 				Label:     "percentage",
-				Min:       0,
-				Max:       100,
+				Min:       ptr(float64(0)),
+				Max:       ptr(float64(100)),
 				ShowUnits: true,
 			},
 			LegendPosition: "bottom",
@@ -182,7 +407,7 @@ func TestGraphWidgetDatasourceSettings_ToCWDashboardBodyWidget(t *testing.T) {
 			},
 			RightYAxis: &graphWidgetYAxisDataSourceSettings{
 				Label:     "bytes",
-				Min:       0,
+				Min:       ptr(float64(0)),
 				ShowUnits: true,
 			},
 			Sparkline: true,
@@ -193,15 +418,11 @@ func TestGraphWidgetDatasourceSettings_ToCWDashboardBodyWidget(t *testing.T) {
 			View:      "timeSeries",
 			Width:     12,
 		}
-		beforeWidgetPosition := &widgetPosition{X: 6, Y: 10}
-
-		cwWidget, err := input.ToCWDashboardBodyWidget(context.TODO(), beforeWidgetPosition)
+		cwWidget, err := input.ToCWDashboardBodyWidget(context.TODO())
 
 		assert.NoError(t, err)
 
 		assert.Equal(t, "metric", cwWidget.Type)
-		assert.Equal(t, int32(6), cwWidget.X)
-		assert.Equal(t, int32(10), cwWidget.Y)
 		assert.Equal(t, int32(12), cwWidget.Width)
 		assert.Equal(t, int32(6), cwWidget.Height)
 
@@ -219,13 +440,13 @@ func TestGraphWidgetDatasourceSettings_ToCWDashboardBodyWidget(t *testing.T) {
 		assert.Equal(t, "+0000", cwWidgetProperties.Timezone)
 		assert.Equal(t, &CWDashboardBodyWidgetPropertyMetricYAxisSide{
 			Label:     "percentage",
-			Min:       0,
-			Max:       100,
+			Min:       ptr(float64(0)),
+			Max:       ptr(float64(100)),
 			ShowUnits: true,
 		}, cwWidgetProperties.YAxis.Left)
 		assert.Equal(t, &CWDashboardBodyWidgetPropertyMetricYAxisSide{
 			Label:     "bytes",
-			Min:       0,
+			Min:       ptr(float64(0)),
 			ShowUnits: true,
 		}, cwWidgetProperties.YAxis.Right)
 		assert.Nil(t, cwWidgetProperties.Table)
@@ -238,11 +459,14 @@ func TestGraphWidgetDatasourceSettings_ToCWDashboardBodyWidget(t *testing.T) {
 			"InstanceId",
 			"i-1234567890abcdef0",
 			map[string]interface{}{
-				"color":  "#ff0000",
-				"label":  "CPU Utilization",
-				"period": int32(300),
-				"stat":   "Average",
-				"yAxis":  "left",
+				"accountId": "123456789012",
+				"color":     "#ff0000",
+				"label":     "CPU Utilization",
+				"period":    int32(300),
+				"region":    "us-east-1",
+				"stat":      "Average",
+				"unit":      "Percent",
+				"yAxis":     "left",
 			},
 		}, cwWidgetProperties.Metrics[0])
 		assert.Equal(t, []interface{}{
@@ -251,13 +475,220 @@ func TestGraphWidgetDatasourceSettings_ToCWDashboardBodyWidget(t *testing.T) {
 			"InstanceId",
 			"i-1234567890abcdef0",
 			map[string]interface{}{
-				"color":  "#0000ff",
-				"label":  "Network In",
-				"period": int32(300),
-				"stat":   "Average",
-				"yAxis":  "right",
+				"accountId": "123456789012",
+				"color":     "#0000ff",
+				"label":     "Network In",
+				"period":    int32(300),
+				"region":    "us-east-1",
+				"stat":      "Average",
+				"unit":      "Bytes",
+				"yAxis":     "right",
 			},
 		}, cwWidgetProperties.Metrics[1])
 
 	})
 }
+
+func TestGraphWidgetDatasourceSettings_ToCWDashboardBodyWidget_Annotations(t *testing.T) {
+	endValue := 200.0
+	hidden := false
+
+	input := graphWidgetDataSourceSettings{
+		Width:  12,
+		Height: 6,
+		LeftAnnotations: []graphWidgetHorizontalAnnotationDataSourceSettings{
+			{Value: 100, Label: "SLO", Color: "#ff0000", Fill: "above"},
+		},
+		RightAnnotations: []graphWidgetHorizontalAnnotationDataSourceSettings{
+			{Value: 150, EndValue: &endValue, Label: "Warning", Fill: "between", Visible: &hidden},
+		},
+		VerticalAnnotations: []graphWidgetVerticalAnnotationDataSourceSettings{
+			{Value: "2024-01-01T00:00:00Z", Label: "Deploy", Fill: "after"},
+			{Value: "2024-01-02T00:00:00Z", EndValue: "2024-01-02T01:00:00Z", Label: "Maintenance", Fill: "between"},
+		},
+	}
+
+	cwWidget, err := input.ToCWDashboardBodyWidget(context.TODO())
+	assert.NoError(t, err)
+
+	b, err := json.Marshal(cwWidget.Properties.(CWDashboardBodyWidgetPropertyMetric).Annotations)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"horizontal": [
+			{"value": 100, "label": "SLO", "color": "#ff0000", "fill": "above", "yAxis": "left"},
+			[{"value": 150, "label": "Warning", "visible": false, "yAxis": "right"}, {"value": 200}]
+		],
+		"vertical": [
+			{"value": "2024-01-01T00:00:00Z", "label": "Deploy", "fill": "after"},
+			[{"value": "2024-01-02T00:00:00Z", "label": "Maintenance"}, {"value": "2024-01-02T01:00:00Z"}]
+		]
+	}`, string(b))
+}
+
+func TestGraphWidgetDatasourceSettings_ToCWDashboardBodyWidget_NoAnnotations(t *testing.T) {
+	input := graphWidgetDataSourceSettings{
+		Width:  12,
+		Height: 6,
+	}
+
+	cwWidget, err := input.ToCWDashboardBodyWidget(context.TODO())
+	assert.NoError(t, err)
+	assert.Nil(t, cwWidget.Properties.(CWDashboardBodyWidgetPropertyMetric).Annotations)
+}
+
+func TestGraphWidgetDatasourceSettings_ToCWDashboardBodyWidget_NoMetrics(t *testing.T) {
+	input := graphWidgetDataSourceSettings{
+		Width:  12,
+		Height: 6,
+		Title:  "Empty",
+	}
+
+	cwWidget, err := input.ToCWDashboardBodyWidget(context.TODO())
+	assert.NoError(t, err)
+
+	// unlike the alarm widget, the graph widget always has metrics, even when there are none
+	b, err := json.Marshal(cwWidget.Properties)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"legend": {"position": ""}, "metrics": [], "region": "", "title": "Empty"}`, string(b))
+}
+
+func TestGraphWidgetDatasourceSettings_ToCWDashboardBodyWidget_Table(t *testing.T) {
+	showTimeSeriesData := false
+
+	input := graphWidgetDataSourceSettings{
+		Width:  24,
+		Height: 6,
+		View:   "table",
+		Table: &graphWidgetTableDataSourceSettings{
+			Layout:             "vertical",
+			ShowTimeSeriesData: &showTimeSeriesData,
+			StickySummary:      true,
+			SummaryColumns:     []string{"MIN", "MAX"},
+		},
+	}
+
+	cwWidget, err := input.ToCWDashboardBodyWidget(context.TODO())
+	assert.NoError(t, err)
+
+	cwWidgetProperties, ok := cwWidget.Properties.(CWDashboardBodyWidgetPropertyMetric)
+	assert.True(t, ok)
+	assert.Equal(t, "table", cwWidgetProperties.View)
+
+	b, err := json.Marshal(cwWidgetProperties.Table)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"layout": "vertical",
+		"stickySummary": true,
+		"showTimeSeriesData": false,
+		"summaryColumns": ["MIN", "MAX"]
+	}`, string(b))
+}
+
+func TestGraphWidgetDatasourceSettings_ToCWDashboardBodyWidget_Views(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    graphWidgetDataSourceSettings
+		expected string
+	}{
+		{
+			name: "gauge keeps a zero minimum",
+			input: graphWidgetDataSourceSettings{
+				View: "gauge",
+				LeftYAxis: &graphWidgetYAxisDataSourceSettings{
+					Min: ptr(float64(0)),
+					Max: ptr(float64(100)),
+				},
+			},
+			expected: `{"legend": {"position": ""}, "metrics": [], "region": "", "view": "gauge", "yAxis": {"left": {"min": 0, "max": 100}}}`,
+		},
+		{
+			name: "pie with labels and legend",
+			input: graphWidgetDataSourceSettings{
+				View:           "pie",
+				LabelsVisible:  ptr(false),
+				LegendPosition: "bottom",
+			},
+			expected: `{"metrics": [], "region": "", "view": "pie", "labels": {"visible": false}, "legend": {"position": "bottom"}}`,
+		},
+		{
+			name: "bar without labels",
+			input: graphWidgetDataSourceSettings{
+				View: "bar",
+			},
+			expected: `{"legend": {"position": ""}, "metrics": [], "region": "", "view": "bar"}`,
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			cwWidget, err := tc.input.ToCWDashboardBodyWidget(context.TODO())
+			assert.NoError(t, err)
+
+			b, err := json.Marshal(cwWidget.Properties)
+			assert.NoError(t, err)
+			assert.JSONEq(t, tc.expected, string(b))
+		})
+	}
+}
+
+func TestGraphWidgetDatasourceSettings_ToCWDashboardBodyWidget_MetricIds(t *testing.T) {
+	requests := `{"type":"metric","metricName":"RequestCount","namespace":"AWS/ApplicationELB","statistic":"Sum"}`
+	errors := `{"type":"metric","metricName":"HTTPCode_Target_5XX_Count","namespace":"AWS/ApplicationELB","statistic":"Sum"}`
+
+	t.Run("should make the ids of the expressions unique in the widget", func(t *testing.T) {
+		input := graphWidgetDataSourceSettings{
+			Left: []IMetricSettings{
+				&metricExpressionDataSourceSettings{
+					Expression:   "m1 * 2",
+					Label:        "doubled",
+					UsingMetrics: map[string]string{"m1": requests},
+				},
+				&metricExpressionDataSourceSettings{
+					Expression:   "RATE(m1)",
+					Label:        "error rate",
+					UsingMetrics: map[string]string{"m1": errors},
+				},
+			},
+			Right: []IMetricSettings{
+				&metricExpressionDataSourceSettings{
+					Expression:   "m1 / 60",
+					Id:           "m1_2",
+					UsingMetrics: map[string]string{"m1": requests},
+				},
+			},
+		}
+
+		cwWidget, err := input.ToCWDashboardBodyWidget(context.TODO())
+		assert.NoError(t, err)
+
+		b, err := json.Marshal(cwWidget.Properties.(CWDashboardBodyWidgetPropertyMetric).Metrics)
+		assert.NoError(t, err)
+
+		// m1_2 is chosen by the user, so the local m1 of the second expression is renamed to m1_3.
+		// The same metric used with the same id in two expressions is rendered once
+		assert.JSONEq(t, `[
+			["AWS/ApplicationELB", "RequestCount", {"id": "m1", "stat": "Sum", "visible": false, "yAxis": "left"}],
+			[{"expression": "m1 * 2", "label": "doubled", "yAxis": "left"}],
+			["AWS/ApplicationELB", "HTTPCode_Target_5XX_Count", {"id": "m1_3", "stat": "Sum", "visible": false, "yAxis": "left"}],
+			[{"expression": "RATE(m1_3)", "label": "error rate", "yAxis": "left"}],
+			[{"expression": "m1 / 60", "id": "m1_2", "yAxis": "right"}]
+		]`, string(b))
+	})
+
+	t.Run("should fail when the same id is chosen for more than one expression", func(t *testing.T) {
+		input := graphWidgetDataSourceSettings{
+			Left: []IMetricSettings{
+				&metricExpressionDataSourceSettings{Expression: "m1 * 2", Id: "e1", UsingMetrics: map[string]string{"m1": requests}},
+			},
+			Right: []IMetricSettings{
+				&metricExpressionDataSourceSettings{Expression: "m1 * 3", Id: "e1", UsingMetrics: map[string]string{"m1": requests}},
+			},
+		}
+
+		_, err := input.ToCWDashboardBodyWidget(context.TODO())
+		assert.EqualError(t, err, `id "e1" is used by more than one metric in the widget`)
+	})
+}
@@ -0,0 +1,261 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource = &logWidgetDataSource{}
+)
+
+type logWidgetDataSource struct {
+}
+
+func NewLogWidgetDataSource() func() datasource.DataSource {
+	return func() datasource.DataSource {
+		return &logWidgetDataSource{}
+	}
+}
+
+func (d *logWidgetDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_log_widget"
+}
+
+func (d *logWidgetDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"log_group_names": schema.ListAttribute{
+				Description: "Names of the log groups to query",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"query": schema.StringAttribute{
+				Description: "The Logs Insights query to run. The `SOURCE` commands for the log groups are prepended automatically.",
+				Required:    true,
+			},
+			"account_id": schema.StringAttribute{
+				Description: "The ID of the account where the logs are located",
+				Optional:    true,
+			},
+			"region": schema.StringAttribute{
+				Description: "The region where the logs are located",
+				Optional:    true,
+			},
+			"title": schema.StringAttribute{
+				Description: "Title for the widget",
+				Optional:    true,
+			},
+			"view": schema.StringAttribute{
+				Description: "How the query results are displayed. " +
+					"Valid Values: `table` | `timeSeries` | `bar` | `pie` | `stackedArea`",
+				Optional: true,
+			},
+			"width": schema.Int32Attribute{
+				Description: "Width of the widget, in a grid of 24 units wide. Defaults to an even share of its row, see `columns` of `cwdashboard`",
+				Optional:    true,
+			},
+			"height": schema.Int32Attribute{
+				Description: "Height of the widget. Defaults to `6`",
+				Optional:    true,
+			},
+			"enabled": schema.BoolAttribute{
+				Description: "Whether the widget is in the dashboard. Defaults to `true`. The other widgets are laid out as if a disabled widget was not there",
+				Optional:    true,
+			},
+			"x": schema.Int32Attribute{
+				Description: "The horizontal position of the widget, in a grid of 24 units wide. Must be set together with `y`. When omitted, the widget is placed automatically",
+				Optional:    true,
+			},
+			"y": schema.Int32Attribute{
+				Description: "The vertical position of the widget. Must be set together with `x`. When omitted, the widget is placed automatically",
+				Optional:    true,
+			},
+
+			"json": schema.StringAttribute{
+				Description: "The settings of the widget",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type logWidgetDataSourceModel struct {
+	LogGroupNames []types.String `tfsdk:"log_group_names"`
+	Query         types.String   `tfsdk:"query"`
+	AccountId     types.String   `tfsdk:"account_id"`
+	Region        types.String   `tfsdk:"region"`
+	Title         types.String   `tfsdk:"title"`
+	View          types.String   `tfsdk:"view"`
+	Width         types.Int32    `tfsdk:"width"`
+	Height        types.Int32    `tfsdk:"height"`
+	X             types.Int32    `tfsdk:"x"`
+	Y             types.Int32    `tfsdk:"y"`
+	Enabled       types.Bool     `tfsdk:"enabled"`
+
+	Json types.String `tfsdk:"json"`
+}
+
+const (
+	logWidgetViewTable       = "table"
+	logWidgetViewTimeSeries  = "timeSeries"
+	logWidgetViewBar         = "bar"
+	logWidgetViewPie         = "pie"
+	logWidgetViewStackedArea = "stackedArea"
+)
+
+func (d *logWidgetDataSourceModel) Validate() error {
+	if err := validateWidgetSize(d.Width, d.Height); err != nil {
+		return err
+	}
+	if err := validateWidgetPosition(d.X, d.Y); err != nil {
+		return err
+	}
+
+	if len(d.LogGroupNames) == 0 {
+		return fmt.Errorf("log_group_names must contain at least one log group")
+	}
+
+	if strings.TrimSpace(d.Query.ValueString()) == "" {
+		return fmt.Errorf("query cannot be empty")
+	}
+
+	if !d.View.IsNull() {
+		view := d.View.ValueString()
+		validViews := map[string]bool{
+			logWidgetViewTable:       true,
+			logWidgetViewTimeSeries:  true,
+			logWidgetViewBar:         true,
+			logWidgetViewPie:         true,
+			logWidgetViewStackedArea: true,
+		}
+		if !validViews[view] {
+			return fmt.Errorf("view must be one of 'table', 'timeSeries', 'bar', 'pie', or 'stackedArea', got: %s", view)
+		}
+	}
+
+	return nil
+}
+
+type logWidgetDataSourceSettings struct {
+	Type          string   `json:"type"`
+	LogGroupNames []string `json:"log_group_names"`
+	Query         string   `json:"query"`
+	AccountId     string   `json:"account_id,omitempty"`
+	Region        string   `json:"region,omitempty"`
+	Title         string   `json:"title,omitempty"`
+	View          string   `json:"view,omitempty"`
+	Width         int32    `json:"width"`
+	Height        int32    `json:"height"`
+	X             *int32   `json:"x,omitempty"`
+	Y             *int32   `json:"y,omitempty"`
+	Enabled       *bool    `json:"enabled,omitempty"`
+}
+
+const (
+	typeLogWidget = "log"
+
+	defaultLogWidgetHeight = 6
+)
+
+func (d *logWidgetDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state logWidgetDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := state.Validate(); err != nil {
+		resp.Diagnostics.AddError("invalid settings", err.Error())
+		return
+	}
+
+	logGroupNames := make([]string, len(state.LogGroupNames))
+	for i, name := range state.LogGroupNames {
+		logGroupNames[i] = name.ValueString()
+	}
+
+	settings := logWidgetDataSourceSettings{
+		Type:          typeLogWidget,
+		LogGroupNames: logGroupNames,
+		Query:         state.Query.ValueString(),
+		AccountId:     state.AccountId.ValueString(),
+		Region:        state.Region.ValueString(),
+		Title:         state.Title.ValueString(),
+		View:          state.View.ValueString(),
+		Width:         state.Width.ValueInt32(),
+		Height:        state.Height.ValueInt32(),
+		X:             state.X.ValueInt32Pointer(),
+		Y:             state.Y.ValueInt32Pointer(),
+		Enabled:       state.Enabled.ValueBoolPointer(),
+	}
+
+	b, err := json.Marshal(settings)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to marshal widget settings", err.Error())
+		return
+	}
+
+	tflog.Info(ctx, "log widget settings", map[string]interface{}{
+		"settings": string(b),
+	})
+
+	state.Json = types.StringValue(string(b))
+
+	stateDiags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(stateDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// buildQuery prepends a SOURCE command for each log group to the query, as the console does.
+func (w logWidgetDataSourceSettings) buildQuery() string {
+	parts := make([]string, 0, len(w.LogGroupNames)+1)
+	for _, name := range w.LogGroupNames {
+		parts = append(parts, fmt.Sprintf("SOURCE '%s'", name))
+	}
+	parts = append(parts, w.Query)
+
+	return strings.Join(parts, " | ")
+}
+
+func (w logWidgetDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Context) (CWDashboardBodyWidget, error) {
+	view := w.View
+	stacked := false
+	// NOTE: stacked area is rendered as a stacked time series
+	if view == logWidgetViewStackedArea {
+		view = logWidgetViewTimeSeries
+		stacked = true
+	}
+
+	cwWidget := CWDashboardBodyWidget{
+		Type:   "log",
+		Width:  w.Width,
+		Height: widgetHeightOrDefault(w.Height, defaultLogWidgetHeight),
+		Properties: CWDashboardBodyWidgetPropertyLog{
+			AccountId: w.AccountId,
+			Region:    w.Region,
+			Title:     w.Title,
+			Query:     w.buildQuery(),
+			View:      view,
+			Stacked:   stacked,
+		},
+	}
+
+	pinWidget(&cwWidget, w.X, w.Y)
+
+	tflog.Debug(ctx, "built log widget", map[string]interface{}{
+		"widget": cwWidget,
+	})
+
+	return cwWidget, nil
+}
@@ -0,0 +1,157 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"github.com/tj/assert"
+)
+
+func TestLogWidgetDataSourceModel_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		model   logWidgetDataSourceModel
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "valid complete model",
+			model: logWidgetDataSourceModel{
+				LogGroupNames: []types.String{types.StringValue("/aws/lambda/my-function")},
+				Query:         types.StringValue("fields @timestamp, @message"),
+				View:          types.StringValue("stackedArea"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid model without view",
+			model: logWidgetDataSourceModel{
+				LogGroupNames: []types.String{types.StringValue("/aws/lambda/my-function")},
+				Query:         types.StringValue("fields @timestamp, @message"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "missing log groups",
+			model: logWidgetDataSourceModel{
+				Query: types.StringValue("fields @timestamp, @message"),
+			},
+			wantErr: true,
+			errMsg:  "log_group_names must contain at least one log group",
+		},
+		{
+			name: "empty query",
+			model: logWidgetDataSourceModel{
+				LogGroupNames: []types.String{types.StringValue("/aws/lambda/my-function")},
+				Query:         types.StringValue(" "),
+			},
+			wantErr: true,
+			errMsg:  "query cannot be empty",
+		},
+		{
+			name: "invalid view",
+			model: logWidgetDataSourceModel{
+				LogGroupNames: []types.String{types.StringValue("/aws/lambda/my-function")},
+				Query:         types.StringValue("fields @timestamp, @message"),
+				View:          types.StringValue("line"),
+			},
+			wantErr: true,
+			errMsg:  "view must be one of 'table', 'timeSeries', 'bar', 'pie', or 'stackedArea', got: line",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.model.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Validate() error = nil, want error")
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Validate() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestLogWidgetDataSourceSettings_ToCWDashboardBodyWidget(t *testing.T) {
+	type testCase struct {
+		name     string
+		widget   logWidgetDataSourceSettings
+		expected CWDashboardBodyWidget
+	}
+
+	tests := []testCase{
+		{
+			name: "should successfully parse when all fields are specified",
+			widget: logWidgetDataSourceSettings{
+				LogGroupNames: []string{"/aws/lambda/a", "/aws/lambda/b"},
+				Query:         "fields @timestamp, @message | limit 20",
+				AccountId:     "123456789012",
+				Region:        "us-east-1",
+				Title:         "Errors",
+				View:          "table",
+				Width:         12,
+				Height:        6,
+			},
+			expected: CWDashboardBodyWidget{
+				Type:   "log",
+				X:      0,
+				Y:      0,
+				Width:  12,
+				Height: 6,
+				Properties: CWDashboardBodyWidgetPropertyLog{
+					AccountId: "123456789012",
+					Region:    "us-east-1",
+					Title:     "Errors",
+					Query:     "SOURCE '/aws/lambda/a' | SOURCE '/aws/lambda/b' | fields @timestamp, @message | limit 20",
+					View:      "table",
+				},
+			},
+		},
+		{
+			name: "should render stacked area as stacked time series",
+			widget: logWidgetDataSourceSettings{
+				LogGroupNames: []string{"/aws/lambda/a"},
+				Query:         "stats count(*) by bin(5m)",
+				View:          "stackedArea",
+				Width:         12,
+				Height:        6,
+			},
+			expected: CWDashboardBodyWidget{
+				Type:   "log",
+				X:      0,
+				Y:      0,
+				Width:  12,
+				Height: 6,
+				Properties: CWDashboardBodyWidgetPropertyLog{
+					Query:   "SOURCE '/aws/lambda/a' | stats count(*) by bin(5m)",
+					View:    "timeSeries",
+					Stacked: true,
+				},
+			},
+		},
+	}
+
+	ctx := context.Background()
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			actual, err := tc.widget.ToCWDashboardBodyWidget(ctx)
+			require.NoError(t, err)
+
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"regexp"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -39,7 +40,7 @@ func (d *metricDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 				Required:    true,
 			},
 			"account": schema.StringAttribute{
-				Description: "Account which this metric comes from",
+				Description: "Account which this metric comes from, as a 12-digit account ID. Defaults to the account of the dashboard",
 				Optional:    true,
 			},
 			"color": schema.StringAttribute{
@@ -47,7 +48,7 @@ func (d *metricDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 				Optional:    true,
 			},
 			"dimensions_map": schema.MapAttribute{
-				Description: "Dimensions of the metric",
+				Description: "Dimensions of the metric. The dimensions are rendered in the order of their names",
 				Optional:    true,
 				ElementType: types.StringType,
 			},
@@ -60,7 +61,7 @@ func (d *metricDataSource) Schema(_ context.Context, _ datasource.SchemaRequest,
 				Optional:    true,
 			},
 			"region": schema.StringAttribute{
-				Description: "Region which this metric comes from",
+				Description: "Region which this metric comes from. Defaults to the region of the widget",
 				Optional:    true,
 			},
 			"statistic": schema.StringAttribute{
@@ -96,6 +97,40 @@ type metricDataSourceModel struct {
 }
 
 var (
+	accountIdPattern = regexp.MustCompile(`^[0-9]{12}$`)
+	regionPattern    = regexp.MustCompile(`^[a-z]{2}(-[a-z]+)+-[0-9]+$`)
+
+	// https://docs.aws.amazon.com/AmazonCloudWatch/latest/APIReference/API_MetricDatum.html
+	validMetricUnits = map[string]bool{
+		"Seconds":          true,
+		"Microseconds":     true,
+		"Milliseconds":     true,
+		"Bytes":            true,
+		"Kilobytes":        true,
+		"Megabytes":        true,
+		"Gigabytes":        true,
+		"Terabytes":        true,
+		"Bits":             true,
+		"Kilobits":         true,
+		"Megabits":         true,
+		"Gigabits":         true,
+		"Terabits":         true,
+		"Percent":          true,
+		"Count":            true,
+		"Bytes/Second":     true,
+		"Kilobytes/Second": true,
+		"Megabytes/Second": true,
+		"Gigabytes/Second": true,
+		"Terabytes/Second": true,
+		"Bits/Second":      true,
+		"Kilobits/Second":  true,
+		"Megabits/Second":  true,
+		"Gigabits/Second":  true,
+		"Terabits/Second":  true,
+		"Count/Second":     true,
+		"None":             true,
+	}
+
 	validMetricStatistics = map[string]bool{
 		"SampleCount": true,
 		"Average":     true,
@@ -132,6 +167,18 @@ func (d *metricDataSourceModel) Validate() error {
 		}
 	}
 
+	if account := d.Account.ValueString(); account != "" && !accountIdPattern.MatchString(account) {
+		return fmt.Errorf("invalid account: %s, must be a 12-digit AWS account ID", account)
+	}
+
+	if region := d.Region.ValueString(); region != "" && !regionPattern.MatchString(region) {
+		return fmt.Errorf("invalid region: %s, must be an AWS region code (e.g., us-east-1)", region)
+	}
+
+	if unit := d.Unit.ValueString(); unit != "" && !validMetricUnits[unit] {
+		return fmt.Errorf("invalid unit: %s", unit)
+	}
+
 	return nil
 }
 
@@ -216,13 +263,24 @@ func (s *metricDataSourceSettings) buildMetricWidgetMetricsSettings(left bool, e
 	settings = append(settings, s.Namespace)
 	settings = append(settings, s.MetricName)
 
-	for dimKey, dimVal := range s.DimensionsMap {
+	// sort keys to make the order of dimensions deterministic
+	dimKeys := make([]string, 0, len(s.DimensionsMap))
+	for dimKey := range s.DimensionsMap {
+		dimKeys = append(dimKeys, dimKey)
+	}
+	sort.Strings(dimKeys)
+
+	for _, dimKey := range dimKeys {
 		settings = append(settings, dimKey)
-		settings = append(settings, dimVal)
+		settings = append(settings, s.DimensionsMap[dimKey])
 	}
 
+	// rendering properties are a map, so they are marshaled with sorted keys
 	renderingProperties := map[string]interface{}{}
 
+	if s.Account != "" {
+		renderingProperties["accountId"] = s.Account
+	}
 	if s.Color != "" {
 		renderingProperties["color"] = s.Color
 	}
@@ -232,9 +290,15 @@ func (s *metricDataSourceSettings) buildMetricWidgetMetricsSettings(left bool, e
 	if s.Period != 0 {
 		renderingProperties["period"] = s.Period
 	}
+	if s.Region != "" {
+		renderingProperties["region"] = s.Region
+	}
 	if s.Statistic != "" {
 		renderingProperties["stat"] = s.Statistic
 	}
+	if s.Unit != "" {
+		renderingProperties["unit"] = s.Unit
+	}
 
 	if left {
 		renderingProperties["yAxis"] = "left"
@@ -1,6 +1,7 @@
 package provider
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/types"
@@ -113,6 +114,43 @@ func TestMetricDataSourceModel_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "invalid color format: #GG0000, must be a six-digit hex color code (e.g., #FF0000)",
 		},
+		{
+			name: "valid cross-account and cross-region metric",
+			model: metricDataSourceModel{
+				Statistic: types.StringValue("Average"),
+				Account:   types.StringValue("123456789012"),
+				Region:    types.StringValue("eu-west-1"),
+				Unit:      types.StringValue("Count/Second"),
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid account",
+			model: metricDataSourceModel{
+				Statistic: types.StringValue("Average"),
+				Account:   types.StringValue("prod"),
+			},
+			wantErr: true,
+			errMsg:  "invalid account: prod, must be a 12-digit AWS account ID",
+		},
+		{
+			name: "invalid region",
+			model: metricDataSourceModel{
+				Statistic: types.StringValue("Average"),
+				Region:    types.StringValue("Tokyo"),
+			},
+			wantErr: true,
+			errMsg:  "invalid region: Tokyo, must be an AWS region code (e.g., us-east-1)",
+		},
+		{
+			name: "invalid unit",
+			model: metricDataSourceModel{
+				Statistic: types.StringValue("Average"),
+				Unit:      types.StringValue("Requests"),
+			},
+			wantErr: true,
+			errMsg:  "invalid unit: Requests",
+		},
 	}
 
 	for _, tt := range tests {
@@ -134,3 +172,34 @@ func TestMetricDataSourceModel_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestMetricDataSourceSettings_BuildMetricWidgetMetricsSettings(t *testing.T) {
+	s := metricDataSourceSettings{
+		MetricName: "RequestCount",
+		Namespace:  "AWS/ApplicationELB",
+		DimensionsMap: map[string]string{
+			"TargetGroup":      "targetgroup/app/0123456789abcdef",
+			"LoadBalancer":     "app/app/0123456789abcdef",
+			"AvailabilityZone": "us-east-1a",
+		},
+		Statistic: "Sum",
+	}
+
+	// the dimensions are sorted by name, whatever the order of the map is
+	for i := 0; i < 20; i++ {
+		actual, err := s.buildMetricWidgetMetricsSettings(true, nil)
+		if err != nil {
+			t.Fatalf("buildMetricWidgetMetricsSettings() error = %v", err)
+		}
+		expected := []interface{}{
+			"AWS/ApplicationELB", "RequestCount",
+			"AvailabilityZone", "us-east-1a",
+			"LoadBalancer", "app/app/0123456789abcdef",
+			"TargetGroup", "targetgroup/app/0123456789abcdef",
+			map[string]interface{}{"stat": "Sum", "yAxis": "left"},
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Fatalf("buildMetricWidgetMetricsSettings() = %v, want %v", actual, expected)
+		}
+	}
+}
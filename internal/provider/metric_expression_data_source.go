@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"regexp"
 	"sort"
+	"strings"
 	"unicode"
 
 	"github.com/hashicorp/terraform-plugin-framework/datasource"
@@ -49,8 +50,27 @@ func (d *metricExpressionDataSource) Schema(_ context.Context, _ datasource.Sche
 				Description: "The period of the metric",
 				Optional:    true,
 			},
+			"id": schema.StringAttribute{
+				Description: "The id of the expression in the widget. Must start with a lowercase letter and only contain alphanumerics, " +
+					"and must not be one of the keys of `using_metrics`. The id is kept as is in the widget, so it must be unique in the widget",
+				Optional: true,
+			},
+			"visible": schema.BoolAttribute{
+				Description: "Whether the expression is drawn in the widget. Defaults to `true`",
+				Optional:    true,
+			},
+			"account": schema.StringAttribute{
+				Description: "Account in which the expression is evaluated, as a 12-digit account ID. Defaults to the account of the dashboard",
+				Optional:    true,
+			},
+			"region": schema.StringAttribute{
+				Description: "Region in which the expression is evaluated. Defaults to the region of the widget",
+				Optional:    true,
+			},
 			"using_metrics": schema.MapAttribute{
-				Description: "The metrics used in the expression",
+				Description: "The metrics used in the expression, by their id in the expression. " +
+					"The values can also be the json of other `cwdashboard_metric_expression`, which are rendered hidden before this expression with their key as id. " +
+					"The keys are local to the expression: when the same key is used for another metric in the widget, it is renamed (e.g. `m1_2`) in the widget and in the expression",
 				Optional:    true,
 				ElementType: types.StringType,
 			},
@@ -67,6 +87,10 @@ type metricExpressionDataSourceModel struct {
 	Color        types.String `tfsdk:"color"`
 	Label        types.String `tfsdk:"label"`
 	Period       types.Int32  `tfsdk:"period"`
+	Id           types.String `tfsdk:"id"`
+	Visible      types.Bool   `tfsdk:"visible"`
+	Account      types.String `tfsdk:"account"`
+	Region       types.String `tfsdk:"region"`
 	UsingMetrics types.Map    `tfsdk:"using_metrics"`
 	Json         types.String `tfsdk:"json"`
 }
@@ -86,6 +110,14 @@ func (m *metricExpressionDataSourceModel) Validate() error {
 		}
 	}
 
+	if account := m.Account.ValueString(); account != "" && !accountIdPattern.MatchString(account) {
+		return fmt.Errorf("invalid account: %s, must be a 12-digit AWS account ID", account)
+	}
+
+	if region := m.Region.ValueString(); region != "" && !regionPattern.MatchString(region) {
+		return fmt.Errorf("invalid region: %s, must be an AWS region code (e.g., us-east-1)", region)
+	}
+
 	// Validate metric variable names in usingMetrics
 	var invalidVarNames []string
 	usingMetrics := m.UsingMetrics.Elements()
@@ -98,6 +130,16 @@ func (m *metricExpressionDataSourceModel) Validate() error {
 		return fmt.Errorf("invalid variable names in expression: %v. Must start with lowercase letter and only contain alphanumerics", invalidVarNames)
 	}
 
+	if !m.Id.IsNull() {
+		id := m.Id.ValueString()
+		if !isValidVariableName(id) {
+			return fmt.Errorf("invalid id: %s. Must start with lowercase letter and only contain alphanumerics", id)
+		}
+		if _, exists := usingMetrics[id]; exists {
+			return fmt.Errorf("id %q is already used in using_metrics", id)
+		}
+	}
+
 	// Validate expression syntax and references
 	expr := m.Expression.ValueString()
 	if expr == "" {
@@ -210,6 +252,10 @@ type metricExpressionDataSourceSettings struct {
 	Color        string            `json:"color"`
 	Label        string            `json:"label"`
 	Period       int32             `json:"period"`
+	Id           string            `json:"id,omitempty"`
+	Visible      *bool             `json:"visible,omitempty"`
+	Account      string            `json:"account,omitempty"`
+	Region       string            `json:"region,omitempty"`
 	UsingMetrics map[string]string `json:"using_metrics"`
 }
 
@@ -251,6 +297,10 @@ func (d *metricExpressionDataSource) Read(ctx context.Context, req datasource.Re
 		Color:        state.Color.ValueString(),
 		Label:        state.Label.ValueString(),
 		Period:       state.Period.ValueInt32(),
+		Id:           state.Id.ValueString(),
+		Visible:      state.Visible.ValueBoolPointer(),
+		Account:      state.Account.ValueString(),
+		Region:       state.Region.ValueString(),
 		UsingMetrics: usingMetrics,
 	}
 
@@ -269,9 +319,162 @@ func (d *metricExpressionDataSource) Read(ctx context.Context, req datasource.Re
 	}
 }
 
-func (s *metricExpressionDataSourceSettings) buildMetricWidgetMetricSettingsList(left bool) ([][]interface{}, error) {
-	settings := make([][]interface{}, 0)
+func (s *metricExpressionDataSourceSettings) buildMetricWidgetMetricSettingsList(left bool, ns *metricIdNamespace) ([][]interface{}, error) {
+	b := metricExpressionTreeBuilder{
+		left:     left,
+		ns:       ns,
+		settings: make([][]interface{}, 0),
+	}
+
+	ids, err := b.addUsingMetrics(s)
+	if err != nil {
+		return nil, err
+	}
+
+	expression := *s
+	expression.Expression = rewriteMetricIds(s.Expression, ids)
+	b.settings = append(b.settings, expression.buildMetricExpressionSettings(left, nil))
+
+	return b.settings, nil
+}
+
+// metricIdNamespace holds the ids of the metrics in a widget.
+// The ids in using_metrics are local to each expression, so they are renamed to be unique in the widget
+type metricIdNamespace struct {
+	used map[string]bool
+
+	// emitted holds the id in the widget of each metric or expression emitted, by its local id and json
+	emitted map[string]string
+}
+
+func newMetricIdNamespace() *metricIdNamespace {
+	return &metricIdNamespace{
+		used:    make(map[string]bool),
+		emitted: make(map[string]string),
+	}
+}
+
+// reserve keeps the id chosen by the user, so that no local id is renamed to it
+func (n *metricIdNamespace) reserve(id string) error {
+	if n.used[id] {
+		return fmt.Errorf("id %q is used by more than one metric in the widget", id)
+	}
+	n.used[id] = true
+
+	return nil
+}
+
+// allocate returns the local id if it is not used in the widget yet, or the first of id_2, id_3, ... which is not
+func (n *metricIdNamespace) allocate(id string) string {
+	widgetId := id
+	for i := 2; n.used[widgetId]; i++ {
+		widgetId = fmt.Sprintf("%s_%d", id, i)
+	}
+	n.used[widgetId] = true
+
+	return widgetId
+}
+
+// reserveMetricIds reserves the ids chosen by the user for the expressions in the widget
+func reserveMetricIds(ns *metricIdNamespace, metrics ...[]IMetricSettings) error {
+	for _, ms := range metrics {
+		for _, m := range ms {
+			if e, ok := m.(*metricExpressionDataSourceSettings); ok && e.Id != "" {
+				if err := ns.reserve(e.Id); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+var (
+	// https://docs.aws.amazon.com/AmazonCloudWatch/latest/APIReference/API_MetricDataQuery.html
+	metricIdPattern = regexp.MustCompile(`^[a-z][a-zA-Z0-9_]*$`)
 
+	expressionIdentifierPattern = regexp.MustCompile(`\b[a-zA-Z_][a-zA-Z0-9_]*`)
+)
+
+// validateMetricIds checks that the ids of the metrics in a widget are valid and unique
+func validateMetricIds(metrics [][]interface{}) error {
+	seen := make(map[string]bool)
+	for _, m := range metrics {
+		if len(m) == 0 {
+			continue
+		}
+		renderingProperties, ok := m[len(m)-1].(map[string]interface{})
+		if !ok {
+			continue
+		}
+		id, ok := renderingProperties["id"].(string)
+		if !ok {
+			continue
+		}
+
+		if !metricIdPattern.MatchString(id) {
+			return fmt.Errorf("invalid id of metric in the widget: %s", id)
+		}
+		if seen[id] {
+			return fmt.Errorf("id %q is used by more than one metric in the widget", id)
+		}
+		seen[id] = true
+	}
+
+	return nil
+}
+
+// rewriteMetricIds replaces the local ids in the expression with their ids in the widget.
+// Quoted strings, like the search expression of SEARCH, are left as they are
+func rewriteMetricIds(expression string, ids map[string]string) string {
+	replace := func(s string) string {
+		return expressionIdentifierPattern.ReplaceAllStringFunc(s, func(id string) string {
+			if widgetId, ok := ids[id]; ok {
+				return widgetId
+			}
+			return id
+		})
+	}
+
+	var sb strings.Builder
+	var quote byte
+	start := 0
+	for i := 0; i < len(expression); i++ {
+		c := expression[i]
+		switch {
+		case quote != 0:
+			if c == quote {
+				sb.WriteString(expression[start : i+1])
+				start = i + 1
+				quote = 0
+			}
+		case c == '\'' || c == '"':
+			sb.WriteString(replace(expression[start:i]))
+			start = i
+			quote = c
+		}
+	}
+	if quote != 0 {
+		sb.WriteString(expression[start:])
+	} else {
+		sb.WriteString(replace(expression[start:]))
+	}
+
+	return sb.String()
+}
+
+// metricExpressionTreeBuilder flattens an expression and the metrics and expressions it uses into the metrics of a widget.
+// The used metrics and expressions are emitted before the expression using them, hidden and with their id in the widget
+type metricExpressionTreeBuilder struct {
+	left     bool
+	ns       *metricIdNamespace
+	settings [][]interface{}
+}
+
+// addUsingMetrics emits the metrics and expressions used by s, and returns their ids in the widget by their keys in using_metrics.
+// The nested expressions are copies of their json, so they cannot make a cycle, and their keys are local to them even if an ancestor uses the same key
+func (b *metricExpressionTreeBuilder) addUsingMetrics(s *metricExpressionDataSourceSettings) (map[string]string, error) {
 	// sort keys to make the order of metrics deterministic
 	keys := make([]string, 0, len(s.UsingMetrics))
 	for id := range s.UsingMetrics {
@@ -279,39 +482,106 @@ func (s *metricExpressionDataSourceSettings) buildMetricWidgetMetricSettingsList
 	}
 	sort.Strings(keys)
 
+	ids := make(map[string]string, len(keys))
 	for _, id := range keys {
 		usingMetric := s.UsingMetrics[id]
-		var m metricDataSourceSettings
-		if err := json.Unmarshal([]byte(usingMetric), &m); err != nil {
+
+		// the same metric with the same id is emitted once in the widget
+		emittedKey := id + "\x00" + usingMetric
+		if widgetId, ok := b.ns.emitted[emittedKey]; ok {
+			ids[id] = widgetId
+			continue
+		}
+
+		var t struct {
+			Type string `json:"type"`
+		}
+		if err := json.Unmarshal([]byte(usingMetric), &t); err != nil {
 			return nil, err
 		}
 
-		ms, err := m.buildMetricWidgetMetricsSettings(left, map[string]interface{}{
-			"id":      id,
-			"visible": false,
-		})
-		if err != nil {
-			return nil, fmt.Errorf("failed to build metric widget metric settings: %w", err)
+		var widgetId string
+		switch t.Type {
+		case typeNameOfMetricExpressionDataSource:
+			var e metricExpressionDataSourceSettings
+			if err := json.Unmarshal([]byte(usingMetric), &e); err != nil {
+				return nil, err
+			}
+
+			nestedIds, err := b.addUsingMetrics(&e)
+			if err != nil {
+				return nil, err
+			}
+
+			widgetId = b.ns.allocate(id)
+			e.Expression = rewriteMetricIds(e.Expression, nestedIds)
+			b.settings = append(b.settings, e.buildMetricExpressionSettings(b.left, map[string]interface{}{
+				"id":      widgetId,
+				"visible": false,
+			}))
+
+		default:
+			var m metricDataSourceSettings
+			if err := json.Unmarshal([]byte(usingMetric), &m); err != nil {
+				return nil, err
+			}
+
+			widgetId = b.ns.allocate(id)
+			ms, err := m.buildMetricWidgetMetricsSettings(b.left, map[string]interface{}{
+				"id":      widgetId,
+				"visible": false,
+			})
+			if err != nil {
+				return nil, fmt.Errorf("failed to build metric widget metric settings: %w", err)
+			}
+
+			b.settings = append(b.settings, ms)
 		}
 
-		settings = append(settings, ms)
+		b.ns.emitted[emittedKey] = widgetId
+		ids[id] = widgetId
 	}
 
-	metricExpressionSettings := []interface{}{
-		map[string]interface{}{
-			"expression": s.Expression,
-		},
+	return ids, nil
+}
+
+func (s *metricExpressionDataSourceSettings) buildMetricExpressionSettings(left bool, extra map[string]interface{}) []interface{} {
+	// the expression has the same rendering properties as the metrics it is built from
+	renderingProperties := map[string]interface{}{
+		"expression": s.Expression,
 	}
 
+	if s.Id != "" {
+		renderingProperties["id"] = s.Id
+	}
+	if s.Account != "" {
+		renderingProperties["accountId"] = s.Account
+	}
 	if s.Color != "" {
-		metricExpressionSettings[0].(map[string]interface{})["color"] = s.Color
+		renderingProperties["color"] = s.Color
 	}
-
 	if s.Label != "" {
-		metricExpressionSettings[0].(map[string]interface{})["label"] = s.Label
+		renderingProperties["label"] = s.Label
+	}
+	if s.Period != 0 {
+		renderingProperties["period"] = s.Period
+	}
+	if s.Region != "" {
+		renderingProperties["region"] = s.Region
+	}
+	if s.Visible != nil {
+		renderingProperties["visible"] = *s.Visible
 	}
 
-	settings = append(settings, metricExpressionSettings)
+	if left {
+		renderingProperties["yAxis"] = "left"
+	} else {
+		renderingProperties["yAxis"] = "right"
+	}
+
+	for k, v := range extra {
+		renderingProperties[k] = v
+	}
 
-	return settings, nil
+	return []interface{}{renderingProperties}
 }
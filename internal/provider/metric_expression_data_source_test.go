@@ -2,6 +2,8 @@ package provider
 
 import (
 	"context"
+	"encoding/json"
+	"reflect"
 	"testing"
 
 	"github.com/hashicorp/terraform-plugin-framework/attr"
@@ -196,6 +198,68 @@ func TestMetricExpressionDataSourceModel_ValidateExpressions(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "valid id, visibility, account and region",
+			model: metricExpressionDataSourceModel{
+				Expression: types.StringValue("m1 * 2"),
+				Id:         types.StringValue("e1"),
+				Visible:    types.BoolValue(false),
+				Account:    types.StringValue("123456789012"),
+				Region:     types.StringValue("us-east-1"),
+				UsingMetrics: createMapFromElements(map[string]string{
+					"m1": "metric1",
+				}),
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid id",
+			model: metricExpressionDataSourceModel{
+				Expression: types.StringValue("m1 * 2"),
+				Id:         types.StringValue("E1"),
+				UsingMetrics: createMapFromElements(map[string]string{
+					"m1": "metric1",
+				}),
+			},
+			wantErr: true,
+			errMsg:  "invalid id: E1. Must start with lowercase letter and only contain alphanumerics",
+		},
+		{
+			name: "id used in using_metrics",
+			model: metricExpressionDataSourceModel{
+				Expression: types.StringValue("m1 * 2"),
+				Id:         types.StringValue("m1"),
+				UsingMetrics: createMapFromElements(map[string]string{
+					"m1": "metric1",
+				}),
+			},
+			wantErr: true,
+			errMsg:  `id "m1" is already used in using_metrics`,
+		},
+		{
+			name: "invalid account",
+			model: metricExpressionDataSourceModel{
+				Expression: types.StringValue("m1 * 2"),
+				Account:    types.StringValue("production"),
+				UsingMetrics: createMapFromElements(map[string]string{
+					"m1": "metric1",
+				}),
+			},
+			wantErr: true,
+			errMsg:  "invalid account: production, must be a 12-digit AWS account ID",
+		},
+		{
+			name: "invalid region",
+			model: metricExpressionDataSourceModel{
+				Expression: types.StringValue("m1 * 2"),
+				Region:     types.StringValue("Tokyo"),
+				UsingMetrics: createMapFromElements(map[string]string{
+					"m1": "metric1",
+				}),
+			},
+			wantErr: true,
+			errMsg:  "invalid region: Tokyo, must be an AWS region code (e.g., us-east-1)",
+		},
 	}
 
 	for _, tt := range tests {
@@ -218,6 +282,43 @@ func TestMetricExpressionDataSourceModel_ValidateExpressions(t *testing.T) {
 	}
 }
 
+func TestMetricExpressionDataSourceSettings_BuildMetricWidgetMetricSettingsList(t *testing.T) {
+	s := metricExpressionDataSourceSettings{
+		Expression: "m1 * 100",
+		Label:      "CPU (%)",
+		Period:     300,
+		Id:         "e1",
+		Visible:    ptr(false),
+		Account:    "123456789012",
+		Region:     "eu-west-1",
+		UsingMetrics: map[string]string{
+			"m1": `{"type":"metric","metricName":"CPUUtilization","namespace":"AWS/EC2","statistic":"Average"}`,
+		},
+	}
+
+	actual, err := s.buildMetricWidgetMetricSettingsList(false, newMetricIdNamespace())
+	if err != nil {
+		t.Fatalf("buildMetricWidgetMetricSettingsList() error = %v", err)
+	}
+
+	expected := [][]interface{}{
+		{"AWS/EC2", "CPUUtilization", map[string]interface{}{"id": "m1", "stat": "Average", "visible": false, "yAxis": "right"}},
+		{map[string]interface{}{
+			"expression": "m1 * 100",
+			"id":         "e1",
+			"accountId":  "123456789012",
+			"label":      "CPU (%)",
+			"period":     int32(300),
+			"region":     "eu-west-1",
+			"visible":    false,
+			"yAxis":      "right",
+		}},
+	}
+	if !reflect.DeepEqual(actual, expected) {
+		t.Errorf("buildMetricWidgetMetricSettingsList() = %v, want %v", actual, expected)
+	}
+}
+
 // Helper function to create types.Map from a map of strings
 func createMapFromElements(elements map[string]string) types.Map {
 	elemMap := make(map[string]attr.Value)
@@ -227,3 +328,156 @@ func createMapFromElements(elements map[string]string) types.Map {
 	m, _ := types.MapValueFrom(context.Background(), types.StringType, elemMap)
 	return m
 }
+
+func TestMetricExpressionDataSourceSettings_BuildMetricWidgetMetricSettingsList_Nested(t *testing.T) {
+	metric := func(name string) string {
+		return `{"type":"metric","metricName":"` + name + `","namespace":"AWS/ApplicationELB","statistic":"Sum"}`
+	}
+	hidden := func(id string) map[string]interface{} {
+		return map[string]interface{}{"id": id, "stat": "Sum", "visible": false, "yAxis": "left"}
+	}
+
+	t.Run("should emit the nested expressions hidden before the expression using them", func(t *testing.T) {
+		errors := `{"type":"metric_expression","expression":"m1 + m2","label":"errors","visible":true,"using_metrics":{` +
+			`"m1":` + jsonString(metric("HTTPCode_Target_5XX_Count")) + `,"m2":` + jsonString(metric("HTTPCode_ELB_5XX_Count")) + `}}`
+		s := metricExpressionDataSourceSettings{
+			Expression: "errors / requests * 100",
+			Label:      "error rate",
+			UsingMetrics: map[string]string{
+				"errors":   errors,
+				"requests": metric("RequestCount"),
+			},
+		}
+
+		actual, err := s.buildMetricWidgetMetricSettingsList(true, newMetricIdNamespace())
+		if err != nil {
+			t.Fatalf("buildMetricWidgetMetricSettingsList() error = %v", err)
+		}
+
+		expected := [][]interface{}{
+			{"AWS/ApplicationELB", "HTTPCode_Target_5XX_Count", hidden("m1")},
+			{"AWS/ApplicationELB", "HTTPCode_ELB_5XX_Count", hidden("m2")},
+			{map[string]interface{}{"expression": "m1 + m2", "id": "errors", "label": "errors", "visible": false, "yAxis": "left"}},
+			{"AWS/ApplicationELB", "RequestCount", hidden("requests")},
+			{map[string]interface{}{"expression": "errors / requests * 100", "label": "error rate", "yAxis": "left"}},
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("buildMetricWidgetMetricSettingsList() = %v, want %v", actual, expected)
+		}
+	})
+
+	t.Run("should emit a metric used by several expressions once", func(t *testing.T) {
+		s := metricExpressionDataSourceSettings{
+			Expression: "a + b",
+			UsingMetrics: map[string]string{
+				"a": `{"type":"metric_expression","expression":"m1 * 2","using_metrics":{"m1":` + jsonString(metric("RequestCount")) + `}}`,
+				"b": `{"type":"metric_expression","expression":"m1 * 3","using_metrics":{"m1":` + jsonString(metric("RequestCount")) + `}}`,
+			},
+		}
+
+		actual, err := s.buildMetricWidgetMetricSettingsList(true, newMetricIdNamespace())
+		if err != nil {
+			t.Fatalf("buildMetricWidgetMetricSettingsList() error = %v", err)
+		}
+		if len(actual) != 4 {
+			t.Errorf("buildMetricWidgetMetricSettingsList() = %v, want 4 metrics", actual)
+		}
+	})
+
+	t.Run("should rename the same id for different metrics", func(t *testing.T) {
+		s := metricExpressionDataSourceSettings{
+			Expression: "e1 + m1",
+			UsingMetrics: map[string]string{
+				"e1": `{"type":"metric_expression","expression":"m1 * 2","using_metrics":{"m1":` + jsonString(metric("HTTPCode_Target_5XX_Count")) + `}}`,
+				"m1": metric("RequestCount"),
+			},
+		}
+
+		actual, err := s.buildMetricWidgetMetricSettingsList(true, newMetricIdNamespace())
+		if err != nil {
+			t.Fatalf("buildMetricWidgetMetricSettingsList() error = %v", err)
+		}
+
+		expected := [][]interface{}{
+			{"AWS/ApplicationELB", "HTTPCode_Target_5XX_Count", hidden("m1")},
+			{map[string]interface{}{"expression": "m1 * 2", "id": "e1", "visible": false, "yAxis": "left"}},
+			{"AWS/ApplicationELB", "RequestCount", hidden("m1_2")},
+			{map[string]interface{}{"expression": "e1 + m1_2", "yAxis": "left"}},
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("buildMetricWidgetMetricSettingsList() = %v, want %v", actual, expected)
+		}
+	})
+
+	t.Run("should keep the keys local to each expression at any depth", func(t *testing.T) {
+		s := metricExpressionDataSourceSettings{
+			Expression: "m1 / m2",
+			UsingMetrics: map[string]string{
+				"m1": `{"type":"metric_expression","expression":"m1 + m2","using_metrics":{` +
+					`"m1":` + jsonString(metric("HTTPCode_Target_5XX_Count")) + `,"m2":` + jsonString(metric("HTTPCode_ELB_5XX_Count")) + `}}`,
+				"m2": metric("RequestCount"),
+			},
+		}
+
+		actual, err := s.buildMetricWidgetMetricSettingsList(true, newMetricIdNamespace())
+		if err != nil {
+			t.Fatalf("buildMetricWidgetMetricSettingsList() error = %v", err)
+		}
+
+		expected := [][]interface{}{
+			{"AWS/ApplicationELB", "HTTPCode_Target_5XX_Count", hidden("m1")},
+			{"AWS/ApplicationELB", "HTTPCode_ELB_5XX_Count", hidden("m2")},
+			{map[string]interface{}{"expression": "m1 + m2", "id": "m1_2", "visible": false, "yAxis": "left"}},
+			{"AWS/ApplicationELB", "RequestCount", hidden("m2_2")},
+			{map[string]interface{}{"expression": "m1_2 / m2_2", "yAxis": "left"}},
+		}
+		if !reflect.DeepEqual(actual, expected) {
+			t.Errorf("buildMetricWidgetMetricSettingsList() = %v, want %v", actual, expected)
+		}
+	})
+}
+
+// jsonString quotes s as a json string, like the json of a data source in using_metrics
+func jsonString(s string) string {
+	b, _ := json.Marshal(s)
+	return string(b)
+}
+
+func TestRewriteMetricIds(t *testing.T) {
+	ids := map[string]string{"m1": "m1_2", "m2": "m2"}
+
+	tests := []struct {
+		name       string
+		expression string
+		expected   string
+	}{
+		{
+			name:       "ids in math",
+			expression: "(m1 + m2) / m1 * 100",
+			expected:   "(m1_2 + m2) / m1_2 * 100",
+		},
+		{
+			name:       "ids in functions",
+			expression: "FILL(m1, 0) + RATE(m2)",
+			expected:   "FILL(m1_2, 0) + RATE(m2)",
+		},
+		{
+			name:       "identifiers containing an id",
+			expression: "m10 + m1",
+			expected:   "m10 + m1_2",
+		},
+		{
+			name:       "quoted strings",
+			expression: `SEARCH('{AWS/EC2,InstanceId} MetricName="m1"', 'Average') + m1`,
+			expected:   `SEARCH('{AWS/EC2,InstanceId} MetricName="m1"', 'Average') + m1_2`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if actual := rewriteMetricIds(tt.expression, ids); actual != tt.expected {
+				t.Errorf("rewriteMetricIds() = %v, want %v", actual, tt.expected)
+			}
+		})
+	}
+}
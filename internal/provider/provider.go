@@ -50,6 +50,18 @@ func (p *cwDashboardProvider) DataSources(_ context.Context) []func() datasource
 		// Widgets
 		NewTextWidgetDataSource(),
 		NewGraphWidgetDataSource(),
+		NewAlarmWidgetDataSource(),
+		NewLogWidgetDataSource(),
+		NewAlarmStatusWidgetDataSource(),
+		NewExplorerWidgetDataSource(),
+		NewSingleValueWidgetDataSource(),
+		NewCustomWidgetDataSource(),
+
+		// Layout
+		NewRowDataSource(),
+		NewColumnDataSource(),
+		NewSectionDataSource(),
+		NewSpacerDataSource(),
 	}
 }
 
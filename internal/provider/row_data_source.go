@@ -0,0 +1,201 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource = &rowDataSource{}
+)
+
+type rowDataSource struct {
+}
+
+func NewRowDataSource() func() datasource.DataSource {
+	return func() datasource.DataSource {
+		return &rowDataSource{}
+	}
+}
+
+func (d *rowDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_row"
+}
+
+func (d *rowDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Places widgets side by side. The width of the row is split evenly among the widgets, so their own widths are ignored. " +
+			"The row can be used in `widgets` of `cwdashboard`, `cwdashboard_row`, `cwdashboard_column` and `cwdashboard_section` like a widget.",
+		Attributes: map[string]schema.Attribute{
+			"widgets": schema.ListAttribute{
+				Description: "The widgets in the row, from left to right",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"width": schema.Int32Attribute{
+				Description: "Width of the row, in a grid of 24 units wide. Defaults to `24`. Ignored when the row is in another row or a column",
+				Optional:    true,
+			},
+			"enabled": schema.BoolAttribute{
+				Description: "Whether the row is in the dashboard. Defaults to `true`. The other widgets are laid out as if a disabled row was not there",
+				Optional:    true,
+			},
+
+			"json": schema.StringAttribute{
+				Description: "The settings of the row",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type rowDataSourceModel struct {
+	Widgets []types.String `tfsdk:"widgets"`
+	Width   types.Int32    `tfsdk:"width"`
+	Enabled types.Bool     `tfsdk:"enabled"`
+
+	Json types.String `tfsdk:"json"`
+}
+
+func (d *rowDataSourceModel) Validate() error {
+	if err := validateGroupWidgets(d.Widgets); err != nil {
+		return err
+	}
+
+	if !d.Width.IsNull() {
+		if width := d.Width.ValueInt32(); width < int32(len(d.Widgets)) || width > MAX_WIDTH {
+			return fmt.Errorf("width must be between %d and %d to hold %d widgets, got: %d", len(d.Widgets), MAX_WIDTH, len(d.Widgets), width)
+		}
+	}
+
+	return nil
+}
+
+// validateGroupWidgets checks the widgets of a row, a column or a section.
+func validateGroupWidgets(widgets []types.String) error {
+	if len(widgets) == 0 {
+		return fmt.Errorf("widgets must contain at least one widget")
+	}
+
+	for i, w := range widgets {
+		var widget map[string]interface{}
+		if err := json.Unmarshal([]byte(w.ValueString()), &widget); err != nil || widget == nil {
+			return fmt.Errorf("widget at index %d is not a widget json", i)
+		}
+		if _, ok := widget["type"].(string); !ok {
+			return fmt.Errorf("widget at index %d is not a widget json", i)
+		}
+	}
+
+	return nil
+}
+
+type rowDataSourceSettings struct {
+	Type    string            `json:"type"`
+	Widgets []json.RawMessage `json:"widgets"`
+	Width   *int32            `json:"width,omitempty"`
+	Enabled *bool             `json:"enabled,omitempty"`
+
+	// Children holds the parsed settings of Widgets
+	Children []interface{} `json:"-"`
+}
+
+const (
+	typeRow = "row"
+)
+
+func (d *rowDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state rowDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := state.Validate(); err != nil {
+		resp.Diagnostics.AddError("invalid settings", err.Error())
+		return
+	}
+
+	widgets := make([]json.RawMessage, len(state.Widgets))
+	for i, w := range state.Widgets {
+		widgets[i] = json.RawMessage(w.ValueString())
+	}
+
+	settings := rowDataSourceSettings{
+		Type:    typeRow,
+		Widgets: widgets,
+		Width:   state.Width.ValueInt32Pointer(),
+		Enabled: state.Enabled.ValueBoolPointer(),
+	}
+
+	b, err := json.Marshal(settings)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to marshal row settings", err.Error())
+		return
+	}
+
+	tflog.Info(ctx, "row settings", map[string]interface{}{
+		"settings": string(b),
+	})
+
+	state.Json = types.StringValue(string(b))
+
+	stateDiags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(stateDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+// buildGroupChildren builds the widgets of a row, a column or a section.
+func buildGroupChildren(ctx context.Context, groupType string, rawWidgets []interface{}) ([]CWDashboardBodyWidget, error) {
+	children := make([]CWDashboardBodyWidget, 0, len(rawWidgets))
+	for i, rawWidget := range rawWidgets {
+		child, err := buildWidget(ctx, rawWidget)
+		if err != nil {
+			return nil, fmt.Errorf("failed to build widget at index %d of %s: %w", i, groupType, err)
+		}
+		if child.Pinned {
+			return nil, fmt.Errorf("widget at index %d of %s cannot have x and y", i, groupType)
+		}
+		if err := validateWidgetBounds(child); err != nil {
+			return nil, fmt.Errorf("invalid widget at index %d of %s: %w", i, groupType, err)
+		}
+		children = append(children, child)
+	}
+
+	return children, nil
+}
+
+func (w rowDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Context) (CWDashboardBodyWidget, error) {
+	children, err := buildGroupChildren(ctx, widgetGroupTypeRow, w.Children)
+	if err != nil {
+		return CWDashboardBodyWidget{}, err
+	}
+
+	width := int32(MAX_WIDTH)
+	if w.Width != nil {
+		width = *w.Width
+	}
+
+	row := CWDashboardBodyWidget{
+		Type:     widgetGroupTypeRow,
+		Children: children,
+	}
+	if err := resizeWidget(&row, width); err != nil {
+		return CWDashboardBodyWidget{}, err
+	}
+
+	tflog.Debug(ctx, "built row", map[string]interface{}{
+		"widget": row,
+	})
+
+	return row, nil
+}
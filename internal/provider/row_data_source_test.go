@@ -0,0 +1,131 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"github.com/tj/assert"
+)
+
+func TestRowDataSourceModel_Validate(t *testing.T) {
+	widget := types.StringValue(`{"type":"text","markdown":"# Hello","width":8,"height":2}`)
+
+	tests := []struct {
+		name    string
+		model   rowDataSourceModel
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "valid complete model",
+			model: rowDataSourceModel{
+				Widgets: []types.String{widget, widget, widget},
+				Width:   types.Int32Value(12),
+			},
+			wantErr: false,
+		},
+		{
+			name:    "no widgets",
+			model:   rowDataSourceModel{},
+			wantErr: true,
+			errMsg:  "widgets must contain at least one widget",
+		},
+		{
+			name: "not a widget json",
+			model: rowDataSourceModel{
+				Widgets: []types.String{widget, types.StringValue(`{"markdown":"# Hello"}`)},
+			},
+			wantErr: true,
+			errMsg:  "widget at index 1 is not a widget json",
+		},
+		{
+			name: "too narrow for the widgets",
+			model: rowDataSourceModel{
+				Widgets: []types.String{widget, widget, widget},
+				Width:   types.Int32Value(2),
+			},
+			wantErr: true,
+			errMsg:  "width must be between 3 and 24 to hold 3 widgets, got: 2",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.model.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Validate() error = nil, want error %v", tt.errMsg)
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Validate() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestRowDataSourceSettings_ToCWDashboardBodyWidget(t *testing.T) {
+	t.Run("should split the width evenly among the widgets", func(t *testing.T) {
+		input := rowDataSourceSettings{
+			Children: []interface{}{
+				textWidgetDataSourceSettings{Width: 6, Height: 2, Markdown: "a"},
+				graphWidgetDataSourceSettings{Width: 6, Height: 6},
+				graphWidgetDataSourceSettings{Width: 6, Height: 4},
+			},
+		}
+
+		actual, err := input.ToCWDashboardBodyWidget(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, widgetGroupTypeRow, actual.Type)
+		assert.Equal(t, int32(24), actual.Width)
+		assert.Equal(t, int32(6), actual.Height)
+		require.Len(t, actual.Children, 3)
+		for i, expected := range []widgetRect{
+			{widgetPosition{X: 0, Y: 0}, widgetSize{Width: 8, Height: 2}},
+			{widgetPosition{X: 8, Y: 0}, widgetSize{Width: 8, Height: 6}},
+			{widgetPosition{X: 16, Y: 0}, widgetSize{Width: 8, Height: 4}},
+		} {
+			child := actual.Children[i]
+			assert.Equal(t, expected, widgetRect{widgetPosition{X: child.X, Y: child.Y}, widgetSize{Width: child.Width, Height: child.Height}})
+		}
+	})
+
+	t.Run("should give the remainder of the width to the first widgets", func(t *testing.T) {
+		input := rowDataSourceSettings{
+			Width: ptr(int32(20)),
+			Children: []interface{}{
+				graphWidgetDataSourceSettings{Width: 1, Height: 6},
+				graphWidgetDataSourceSettings{Width: 1, Height: 6},
+				graphWidgetDataSourceSettings{Width: 1, Height: 6},
+			},
+		}
+
+		actual, err := input.ToCWDashboardBodyWidget(context.Background())
+		require.NoError(t, err)
+
+		widths := make([]int32, 0, len(actual.Children))
+		for _, child := range actual.Children {
+			widths = append(widths, child.Width)
+		}
+		assert.Equal(t, []int32{7, 7, 6}, widths)
+	})
+
+	t.Run("should reject a pinned widget", func(t *testing.T) {
+		input := rowDataSourceSettings{
+			Children: []interface{}{
+				textWidgetDataSourceSettings{Width: 6, Height: 2, Markdown: "a", X: ptr(int32(0)), Y: ptr(int32(0))},
+			},
+		}
+
+		_, err := input.ToCWDashboardBodyWidget(context.Background())
+		assert.EqualError(t, err, "widget at index 0 of row cannot have x and y")
+	})
+}
@@ -0,0 +1,164 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource = &sectionDataSource{}
+)
+
+type sectionDataSource struct {
+}
+
+func NewSectionDataSource() func() datasource.DataSource {
+	return func() datasource.DataSource {
+		return &sectionDataSource{}
+	}
+}
+
+func (d *sectionDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_section"
+}
+
+func (d *sectionDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Groups widgets under a full-width header. The widgets are laid out below the header from left to right. " +
+			"The section can be used in `widgets` of `cwdashboard`, `cwdashboard_row`, `cwdashboard_column` and `cwdashboard_section` like a widget, " +
+			"and is listed in the table of contents of `cwdashboard`. " +
+			"The dashboard body of CloudWatch has no collapsible widget, so the section cannot be collapsed.",
+		Attributes: map[string]schema.Attribute{
+			"title": schema.StringAttribute{
+				Description: "The title of the section, shown in the header",
+				Required:    true,
+			},
+			"widgets": schema.ListAttribute{
+				Description: "The widgets in the section",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"enabled": schema.BoolAttribute{
+				Description: "Whether the section is in the dashboard. Defaults to `true`. The other widgets are laid out as if a disabled section was not there",
+				Optional:    true,
+			},
+
+			"json": schema.StringAttribute{
+				Description: "The settings of the section",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type sectionDataSourceModel struct {
+	Title   types.String   `tfsdk:"title"`
+	Widgets []types.String `tfsdk:"widgets"`
+	Enabled types.Bool     `tfsdk:"enabled"`
+
+	Json types.String `tfsdk:"json"`
+}
+
+func (d *sectionDataSourceModel) Validate() error {
+	if strings.TrimSpace(d.Title.ValueString()) == "" {
+		return fmt.Errorf("title cannot be empty")
+	}
+
+	return validateGroupWidgets(d.Widgets)
+}
+
+type sectionDataSourceSettings struct {
+	Type    string            `json:"type"`
+	Title   string            `json:"title"`
+	Widgets []json.RawMessage `json:"widgets"`
+	Enabled *bool             `json:"enabled,omitempty"`
+
+	// Children holds the parsed settings of Widgets
+	Children []interface{} `json:"-"`
+}
+
+const (
+	typeSection = "section"
+
+	sectionHeaderHeight = 1
+)
+
+func (d *sectionDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state sectionDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := state.Validate(); err != nil {
+		resp.Diagnostics.AddError("invalid settings", err.Error())
+		return
+	}
+
+	widgets := make([]json.RawMessage, len(state.Widgets))
+	for i, w := range state.Widgets {
+		widgets[i] = json.RawMessage(w.ValueString())
+	}
+
+	settings := sectionDataSourceSettings{
+		Type:    typeSection,
+		Title:   state.Title.ValueString(),
+		Widgets: widgets,
+		Enabled: state.Enabled.ValueBoolPointer(),
+	}
+
+	b, err := json.Marshal(settings)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to marshal section settings", err.Error())
+		return
+	}
+
+	tflog.Info(ctx, "section settings", map[string]interface{}{
+		"settings": string(b),
+	})
+
+	state.Json = types.StringValue(string(b))
+
+	stateDiags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(stateDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (w sectionDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Context) (CWDashboardBodyWidget, error) {
+	children, err := buildGroupChildren(ctx, widgetGroupTypeSection, w.Children)
+	if err != nil {
+		return CWDashboardBodyWidget{}, err
+	}
+
+	header := CWDashboardBodyWidget{
+		Type:   typeTextWidget,
+		Height: sectionHeaderHeight,
+		Properties: CWDashboardBodyWidgetPropertyText{
+			Markdown: "## " + w.Title,
+		},
+	}
+
+	section := CWDashboardBodyWidget{
+		Type:     widgetGroupTypeSection,
+		Children: append([]CWDashboardBodyWidget{header}, children...),
+	}
+	if err := resizeWidget(&section, MAX_WIDTH); err != nil {
+		return CWDashboardBodyWidget{}, err
+	}
+
+	tflog.Debug(ctx, "built section", map[string]interface{}{
+		"widget": section,
+	})
+
+	return section, nil
+}
@@ -0,0 +1,113 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"github.com/tj/assert"
+)
+
+func TestSectionDataSourceModel_Validate(t *testing.T) {
+	widget := types.StringValue(`{"type":"text","markdown":"# Hello","width":8,"height":2}`)
+
+	tests := []struct {
+		name    string
+		model   sectionDataSourceModel
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "valid complete model",
+			model: sectionDataSourceModel{
+				Title:   types.StringValue("Database"),
+				Widgets: []types.String{widget, widget},
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty title",
+			model: sectionDataSourceModel{
+				Title:   types.StringValue(" "),
+				Widgets: []types.String{widget},
+			},
+			wantErr: true,
+			errMsg:  "title cannot be empty",
+		},
+		{
+			name: "no widgets",
+			model: sectionDataSourceModel{
+				Title: types.StringValue("Database"),
+			},
+			wantErr: true,
+			errMsg:  "widgets must contain at least one widget",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.model.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Validate() error = nil, want error %v", tt.errMsg)
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Validate() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestSectionDataSourceSettings_ToCWDashboardBodyWidget(t *testing.T) {
+	t.Run("should place a full-width header above the widgets", func(t *testing.T) {
+		input := sectionDataSourceSettings{
+			Title: "Database",
+			Children: []interface{}{
+				graphWidgetDataSourceSettings{Width: 12, Height: 6},
+				graphWidgetDataSourceSettings{Width: 12, Height: 4},
+				graphWidgetDataSourceSettings{Height: 3},
+			},
+		}
+
+		actual, err := input.ToCWDashboardBodyWidget(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, widgetGroupTypeSection, actual.Type)
+		assert.Equal(t, int32(24), actual.Width)
+		assert.Equal(t, int32(10), actual.Height)
+		require.Len(t, actual.Children, 4)
+
+		header := actual.Children[0]
+		assert.Equal(t, typeTextWidget, header.Type)
+		assert.Equal(t, CWDashboardBodyWidgetPropertyText{Markdown: "## Database"}, header.Properties)
+
+		for i, expected := range []widgetRect{
+			{widgetPosition{X: 0, Y: 0}, widgetSize{Width: 24, Height: 1}},
+			{widgetPosition{X: 0, Y: 1}, widgetSize{Width: 12, Height: 6}},
+			{widgetPosition{X: 12, Y: 1}, widgetSize{Width: 12, Height: 4}},
+			{widgetPosition{X: 0, Y: 7}, widgetSize{Width: 24, Height: 3}},
+		} {
+			child := actual.Children[i]
+			assert.Equal(t, expected, widgetRect{widgetPosition{X: child.X, Y: child.Y}, widgetSize{Width: child.Width, Height: child.Height}})
+		}
+	})
+
+	t.Run("should reject a pinned widget", func(t *testing.T) {
+		input := sectionDataSourceSettings{
+			Title: "Database",
+			Children: []interface{}{
+				textWidgetDataSourceSettings{Width: 6, Height: 2, Markdown: "a", X: ptr(int32(0)), Y: ptr(int32(0))},
+			},
+		}
+
+		_, err := input.ToCWDashboardBodyWidget(context.Background())
+		assert.EqualError(t, err, "widget at index 0 of section cannot have x and y")
+	})
+}
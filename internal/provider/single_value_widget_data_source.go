@@ -0,0 +1,339 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource = &singleValueWidgetDataSource{}
+)
+
+type singleValueWidgetDataSource struct {
+}
+
+func NewSingleValueWidgetDataSource() func() datasource.DataSource {
+	return func() datasource.DataSource {
+		return &singleValueWidgetDataSource{}
+	}
+}
+
+func (d *singleValueWidgetDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_single_value_widget"
+}
+
+func (d *singleValueWidgetDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Attributes: map[string]schema.Attribute{
+			"metrics": schema.ListAttribute{
+				Description: "Metrics to display",
+				Required:    true,
+				ElementType: types.StringType,
+			},
+			"period": schema.Int32Attribute{
+				Description: "The default period for all metrics in this widget",
+				Optional:    true,
+			},
+			"region": schema.StringAttribute{
+				Description: "The region the metrics of this widget should be taken from",
+				Optional:    true,
+			},
+			"set_period_to_time_range": schema.BoolAttribute{
+				Description: "Whether to show the value for the entire time range of the dashboard instead of the most recent value. " +
+					"Cannot be combined with `sparkline` or `trend`",
+				Optional: true,
+			},
+			"single_value_full_precision": schema.BoolAttribute{
+				Description: "Whether to show the value with full precision instead of rounding it",
+				Optional:    true,
+			},
+			"sparkline": schema.BoolAttribute{
+				Description: "Whether to show a sparkline below the value",
+				Optional:    true,
+			},
+			"title": schema.StringAttribute{
+				Description: "Title for the widget",
+				Optional:    true,
+			},
+			"trend": schema.BoolAttribute{
+				Description: "Whether to show the trend compared to the previous period. Defaults to `true`",
+				Optional:    true,
+			},
+			"width": schema.Int32Attribute{
+				Description: "Width of the widget, in a grid of 24 units wide. Defaults to an even share of its row, see `columns` of `cwdashboard`",
+				Optional:    true,
+			},
+			"height": schema.Int32Attribute{
+				Description: "Height of the widget. Defaults to `3`",
+				Optional:    true,
+			},
+			"enabled": schema.BoolAttribute{
+				Description: "Whether the widget is in the dashboard. Defaults to `true`. The other widgets are laid out as if a disabled widget was not there",
+				Optional:    true,
+			},
+			"x": schema.Int32Attribute{
+				Description: "The horizontal position of the widget, in a grid of 24 units wide. Must be set together with `y`. When omitted, the widget is placed automatically",
+				Optional:    true,
+			},
+			"y": schema.Int32Attribute{
+				Description: "The vertical position of the widget. Must be set together with `x`. When omitted, the widget is placed automatically",
+				Optional:    true,
+			},
+
+			"json": schema.StringAttribute{
+				Description: "The settings of the widget",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type singleValueWidgetDataSourceModel struct {
+	Metrics                  []types.String `tfsdk:"metrics"` // JSON string containing array of metrics
+	Period                   types.Int32    `tfsdk:"period"`
+	Region                   types.String   `tfsdk:"region"`
+	SetPeriodToTimeRange     types.Bool     `tfsdk:"set_period_to_time_range"`
+	SingleValueFullPrecision types.Bool     `tfsdk:"single_value_full_precision"`
+	Sparkline                types.Bool     `tfsdk:"sparkline"`
+	Title                    types.String   `tfsdk:"title"`
+	Trend                    types.Bool     `tfsdk:"trend"`
+	Width                    types.Int32    `tfsdk:"width"`
+	Height                   types.Int32    `tfsdk:"height"`
+	X                        types.Int32    `tfsdk:"x"`
+	Y                        types.Int32    `tfsdk:"y"`
+	Enabled                  types.Bool     `tfsdk:"enabled"`
+
+	Json types.String `tfsdk:"json"`
+}
+
+func (d *singleValueWidgetDataSourceModel) Validate() error {
+	if err := validateWidgetSize(d.Width, d.Height); err != nil {
+		return err
+	}
+	if err := validateWidgetPosition(d.X, d.Y); err != nil {
+		return err
+	}
+
+	if len(d.Metrics) == 0 {
+		return fmt.Errorf("metrics must contain at least one metric")
+	}
+
+	if !d.Period.IsNull() {
+		if period := d.Period.ValueInt32(); period < 60 || period%60 != 0 {
+			return fmt.Errorf("period must be 60 or a multiple of 60, got: %d", period)
+		}
+	}
+
+	// CloudWatch ignores the sparkline and the trend when a single value is computed for the whole time range
+	if d.SetPeriodToTimeRange.ValueBool() {
+		if d.Sparkline.ValueBool() {
+			return fmt.Errorf("sparkline cannot be enabled when set_period_to_time_range is true")
+		}
+		if d.Trend.ValueBool() {
+			return fmt.Errorf("trend cannot be enabled when set_period_to_time_range is true")
+		}
+	}
+
+	return nil
+}
+
+type singleValueWidgetDataSourceSettings struct {
+	Type                     string            `json:"type"`
+	Metrics                  []IMetricSettings `json:"metrics"`
+	Period                   int32             `json:"period,omitempty"`
+	Region                   string            `json:"region,omitempty"`
+	SetPeriodToTimeRange     bool              `json:"set_period_to_time_range,omitempty"`
+	SingleValueFullPrecision bool              `json:"single_value_full_precision,omitempty"`
+	Sparkline                bool              `json:"sparkline,omitempty"`
+	Title                    string            `json:"title,omitempty"`
+	Trend                    *bool             `json:"trend,omitempty"`
+	Width                    int32             `json:"width"`
+	Height                   int32             `json:"height"`
+	X                        *int32            `json:"x,omitempty"`
+	Y                        *int32            `json:"y,omitempty"`
+	Enabled                  *bool             `json:"enabled,omitempty"`
+}
+
+func (s *singleValueWidgetDataSourceSettings) UnmarshalJSON(data []byte) error {
+	var intermediate struct {
+		Type                     string `json:"type"`
+		Period                   int32  `json:"period,omitempty"`
+		Region                   string `json:"region,omitempty"`
+		SetPeriodToTimeRange     bool   `json:"set_period_to_time_range,omitempty"`
+		SingleValueFullPrecision bool   `json:"single_value_full_precision,omitempty"`
+		Sparkline                bool   `json:"sparkline,omitempty"`
+		Title                    string `json:"title,omitempty"`
+		Trend                    *bool  `json:"trend,omitempty"`
+		Width                    int32  `json:"width"`
+		Height                   int32  `json:"height"`
+		X                        *int32 `json:"x,omitempty"`
+		Y                        *int32 `json:"y,omitempty"`
+		Enabled                  *bool  `json:"enabled,omitempty"`
+		// Metrics has multiple types, so we need to unmarshal them separately
+		Metrics []interface{} `json:"metrics"`
+	}
+
+	if err := json.Unmarshal(data, &intermediate); err != nil {
+		return fmt.Errorf("failed to unmarshal: %w", err)
+	}
+
+	metrics, err := processMetrics(intermediate.Metrics)
+	if err != nil {
+		return err
+	}
+
+	s.Type = intermediate.Type
+	s.Metrics = metrics
+	s.Period = intermediate.Period
+	s.Region = intermediate.Region
+	s.SetPeriodToTimeRange = intermediate.SetPeriodToTimeRange
+	s.SingleValueFullPrecision = intermediate.SingleValueFullPrecision
+	s.Sparkline = intermediate.Sparkline
+	s.Title = intermediate.Title
+	s.Trend = intermediate.Trend
+	s.Width = intermediate.Width
+	s.Height = intermediate.Height
+	s.X = intermediate.X
+	s.Y = intermediate.Y
+	s.Enabled = intermediate.Enabled
+
+	return nil
+}
+
+const (
+	typeSingleValueWidget = "single_value"
+
+	defaultSingleValueWidgetHeight = 3
+)
+
+func (d *singleValueWidgetDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state singleValueWidgetDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := state.Validate(); err != nil {
+		resp.Diagnostics.AddError("invalid settings", err.Error())
+		return
+	}
+
+	metrics := make([]IMetricSettings, len(state.Metrics))
+	for i, metricJson := range state.Metrics {
+		metric := &metricDataSourceSettings{}
+		if err := json.Unmarshal([]byte(metricJson.ValueString()), &metric); err != nil {
+			resp.Diagnostics.AddError("failed to unmarshal metric", err.Error())
+			return
+		}
+
+		if metric.Type != typeNameOfMetricDataSource {
+			metric := &metricExpressionDataSourceSettings{}
+			if err := json.Unmarshal([]byte(metricJson.ValueString()), &metric); err != nil {
+				resp.Diagnostics.AddError("failed to unmarshal metric", err.Error())
+				return
+			}
+			metrics[i] = metric
+		} else {
+			metrics[i] = metric
+		}
+	}
+
+	settings := singleValueWidgetDataSourceSettings{
+		Type:                     typeSingleValueWidget,
+		Metrics:                  metrics,
+		Period:                   state.Period.ValueInt32(),
+		Region:                   state.Region.ValueString(),
+		SetPeriodToTimeRange:     state.SetPeriodToTimeRange.ValueBool(),
+		SingleValueFullPrecision: state.SingleValueFullPrecision.ValueBool(),
+		Sparkline:                state.Sparkline.ValueBool(),
+		Title:                    state.Title.ValueString(),
+		Trend:                    state.Trend.ValueBoolPointer(),
+		Width:                    state.Width.ValueInt32(),
+		Height:                   state.Height.ValueInt32(),
+		X:                        state.X.ValueInt32Pointer(),
+		Y:                        state.Y.ValueInt32Pointer(),
+		Enabled:                  state.Enabled.ValueBoolPointer(),
+	}
+
+	b, err := json.Marshal(settings)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to marshal widget settings", err.Error())
+		return
+	}
+
+	tflog.Info(ctx, "single value widget settings", map[string]interface{}{
+		"settings": string(b),
+	})
+
+	state.Json = types.StringValue(string(b))
+
+	stateDiags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(stateDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (w singleValueWidgetDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Context) (CWDashboardBodyWidget, error) {
+	// the ids in the expressions are made unique in the widget, keeping the ids chosen by the user
+	ns := newMetricIdNamespace()
+	if err := reserveMetricIds(ns, w.Metrics); err != nil {
+		return CWDashboardBodyWidget{}, err
+	}
+
+	metrics := make([][]interface{}, 0)
+	for _, metric := range w.Metrics {
+		switch m := metric.(type) {
+		case *metricDataSourceSettings:
+			settings, err := m.buildMetricWidgetMetricsSettings(true, nil)
+			if err != nil {
+				return CWDashboardBodyWidget{}, fmt.Errorf("failed to build metric settings: %w", err)
+			}
+			metrics = append(metrics, settings)
+		case *metricExpressionDataSourceSettings:
+			settingsList, err := m.buildMetricWidgetMetricSettingsList(true, ns)
+			if err != nil {
+				return CWDashboardBodyWidget{}, fmt.Errorf("failed to build metric settings: %w", err)
+			}
+			metrics = append(metrics, settingsList...)
+		default:
+			return CWDashboardBodyWidget{}, fmt.Errorf("unsupported metric type: %T", metric)
+		}
+	}
+
+	if err := validateMetricIds(metrics); err != nil {
+		return CWDashboardBodyWidget{}, err
+	}
+
+	cwWidget := CWDashboardBodyWidget{
+		Type:   "metric",
+		Width:  w.Width,
+		Height: widgetHeightOrDefault(w.Height, defaultSingleValueWidgetHeight),
+		Properties: CWDashboardBodyWidgetPropertyMetric{
+			Metrics:                  metrics,
+			Period:                   w.Period,
+			Region:                   w.Region,
+			SetPeriodToTimeRange:     w.SetPeriodToTimeRange,
+			SingleValueFullPrecision: w.SingleValueFullPrecision,
+			Sparkline:                w.Sparkline,
+			Title:                    w.Title,
+			Trend:                    w.Trend,
+			View:                     "singleValue",
+		},
+	}
+
+	pinWidget(&cwWidget, w.X, w.Y)
+
+	tflog.Debug(ctx, "built single value widget", map[string]interface{}{
+		"widget": cwWidget,
+	})
+
+	return cwWidget, nil
+}
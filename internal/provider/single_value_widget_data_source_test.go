@@ -0,0 +1,156 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/tj/assert"
+)
+
+func TestSingleValueWidgetDataSourceModel_Validate(t *testing.T) {
+	metric := types.StringValue(`{"type":"metric","metricName":"CPUUtilization","namespace":"AWS/EC2"}`)
+
+	tests := []struct {
+		name    string
+		model   singleValueWidgetDataSourceModel
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "valid complete model",
+			model: singleValueWidgetDataSourceModel{
+				Metrics:                  []types.String{metric},
+				Period:                   types.Int32Value(300),
+				Region:                   types.StringValue("us-east-1"),
+				SingleValueFullPrecision: types.BoolValue(true),
+				Sparkline:                types.BoolValue(true),
+				Title:                    types.StringValue("CPU"),
+				Trend:                    types.BoolValue(true),
+				Width:                    types.Int32Value(6),
+				Height:                   types.Int32Value(3),
+			},
+			wantErr: false,
+		},
+		{
+			name: "valid value for the entire time range",
+			model: singleValueWidgetDataSourceModel{
+				Metrics:              []types.String{metric},
+				SetPeriodToTimeRange: types.BoolValue(true),
+				Trend:                types.BoolValue(false),
+			},
+			wantErr: false,
+		},
+		{
+			name:    "no metrics",
+			model:   singleValueWidgetDataSourceModel{},
+			wantErr: true,
+			errMsg:  "metrics must contain at least one metric",
+		},
+		{
+			name: "invalid period",
+			model: singleValueWidgetDataSourceModel{
+				Metrics: []types.String{metric},
+				Period:  types.Int32Value(90),
+			},
+			wantErr: true,
+			errMsg:  "period must be 60 or a multiple of 60, got: 90",
+		},
+		{
+			name: "sparkline for the entire time range",
+			model: singleValueWidgetDataSourceModel{
+				Metrics:              []types.String{metric},
+				SetPeriodToTimeRange: types.BoolValue(true),
+				Sparkline:            types.BoolValue(true),
+			},
+			wantErr: true,
+			errMsg:  "sparkline cannot be enabled when set_period_to_time_range is true",
+		},
+		{
+			name: "trend for the entire time range",
+			model: singleValueWidgetDataSourceModel{
+				Metrics:              []types.String{metric},
+				SetPeriodToTimeRange: types.BoolValue(true),
+				Trend:                types.BoolValue(true),
+			},
+			wantErr: true,
+			errMsg:  "trend cannot be enabled when set_period_to_time_range is true",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.model.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Validate() error = nil, want error %v", tt.errMsg)
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Validate() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestSingleValueWidgetDataSourceSettings_ToCWDashboardBodyWidget(t *testing.T) {
+	input := singleValueWidgetDataSourceSettings{
+		Metrics: []IMetricSettings{
+			&metricDataSourceSettings{
+				MetricName: "CPUUtilization",
+				Namespace:  "AWS/EC2",
+				DimensionsMap: map[string]string{
+					"InstanceId": "i-1234567890abcdef0",
+				},
+				Statistic: "Average",
+			},
+		},
+		Period:                   300,
+		Region:                   "us-east-1",
+		SingleValueFullPrecision: true,
+		Sparkline:                true,
+		Title:                    "CPU",
+		Trend:                    ptr(false),
+		Width:                    6,
+		Height:                   3,
+	}
+
+	cwWidget, err := input.ToCWDashboardBodyWidget(context.TODO())
+	assert.NoError(t, err)
+
+	assert.Equal(t, "metric", cwWidget.Type)
+	assert.Equal(t, int32(6), cwWidget.Width)
+	assert.Equal(t, int32(3), cwWidget.Height)
+
+	b, err := json.Marshal(cwWidget.Properties)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{
+		"metrics": [["AWS/EC2", "CPUUtilization", "InstanceId", "i-1234567890abcdef0", {"stat": "Average", "yAxis": "left"}]],
+		"period": 300,
+		"region": "us-east-1",
+		"singleValueFullPrecision": true,
+		"sparkline": true,
+		"title": "CPU",
+		"trend": false,
+		"view": "singleValue"
+	}`, string(b))
+}
+
+func TestSingleValueWidgetDataSourceSettings_UnmarshalJSON(t *testing.T) {
+	data := `{"type":"single_value","metrics":[{"type":"metric","metricName":"CPUUtilization","namespace":"AWS/EC2"}],"set_period_to_time_range":true,"width":6,"height":3}`
+
+	var settings singleValueWidgetDataSourceSettings
+	assert.NoError(t, json.Unmarshal([]byte(data), &settings))
+
+	assert.Equal(t, typeSingleValueWidget, settings.Type)
+	assert.True(t, settings.SetPeriodToTimeRange)
+	assert.Nil(t, settings.Trend)
+	assert.Len(t, settings.Metrics, 1)
+	assert.IsType(t, &metricDataSourceSettings{}, settings.Metrics[0])
+}
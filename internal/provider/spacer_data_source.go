@@ -0,0 +1,155 @@
+package provider
+
+import (
+	"context"
+	"encoding/json"
+
+	"github.com/hashicorp/terraform-plugin-framework/datasource"
+	"github.com/hashicorp/terraform-plugin-framework/datasource/schema"
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/hashicorp/terraform-plugin-log/tflog"
+)
+
+var (
+	_ datasource.DataSource = &spacerDataSource{}
+)
+
+type spacerDataSource struct {
+}
+
+func NewSpacerDataSource() func() datasource.DataSource {
+	return func() datasource.DataSource {
+		return &spacerDataSource{}
+	}
+}
+
+func (d *spacerDataSource) Metadata(_ context.Context, req datasource.MetadataRequest, resp *datasource.MetadataResponse) {
+	resp.TypeName = req.ProviderTypeName + "_spacer"
+}
+
+func (d *spacerDataSource) Schema(_ context.Context, _ datasource.SchemaRequest, resp *datasource.SchemaResponse) {
+	resp.Schema = schema.Schema{
+		Description: "Takes up cells of the grid without rendering anything, to leave a gap between widgets. " +
+			"The spacer is laid out like a widget, but is not included in the dashboard json.",
+		Attributes: map[string]schema.Attribute{
+			"width": schema.Int32Attribute{
+				Description: "Width of the spacer, in a grid of 24 units wide. Defaults to an even share of its row, see `columns` of `cwdashboard`",
+				Optional:    true,
+			},
+			"height": schema.Int32Attribute{
+				Description: "Height of the spacer. Defaults to `1`",
+				Optional:    true,
+			},
+			"enabled": schema.BoolAttribute{
+				Description: "Whether the spacer is in the dashboard. Defaults to `true`. The other widgets are laid out as if a disabled spacer was not there",
+				Optional:    true,
+			},
+			"x": schema.Int32Attribute{
+				Description: "The horizontal position of the spacer, in a grid of 24 units wide. Must be set together with `y`. When omitted, the spacer is placed automatically",
+				Optional:    true,
+			},
+			"y": schema.Int32Attribute{
+				Description: "The vertical position of the spacer. Must be set together with `x`. When omitted, the spacer is placed automatically",
+				Optional:    true,
+			},
+
+			"json": schema.StringAttribute{
+				Description: "The settings of the spacer",
+				Computed:    true,
+			},
+		},
+	}
+}
+
+type spacerDataSourceModel struct {
+	Width   types.Int32 `tfsdk:"width"`
+	Height  types.Int32 `tfsdk:"height"`
+	X       types.Int32 `tfsdk:"x"`
+	Y       types.Int32 `tfsdk:"y"`
+	Enabled types.Bool  `tfsdk:"enabled"`
+
+	Json types.String `tfsdk:"json"`
+}
+
+func (d *spacerDataSourceModel) Validate() error {
+	if err := validateWidgetSize(d.Width, d.Height); err != nil {
+		return err
+	}
+	if err := validateWidgetPosition(d.X, d.Y); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+type spacerDataSourceSettings struct {
+	Type    string `json:"type"`
+	Width   int32  `json:"width"`
+	Height  int32  `json:"height"`
+	X       *int32 `json:"x,omitempty"`
+	Y       *int32 `json:"y,omitempty"`
+	Enabled *bool  `json:"enabled,omitempty"`
+}
+
+const (
+	typeSpacer = "spacer"
+
+	defaultSpacerHeight = 1
+)
+
+func (d *spacerDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
+	var state spacerDataSourceModel
+
+	resp.Diagnostics.Append(req.Config.Get(ctx, &state)...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+
+	if err := state.Validate(); err != nil {
+		resp.Diagnostics.AddError("invalid settings", err.Error())
+		return
+	}
+
+	settings := spacerDataSourceSettings{
+		Type:    typeSpacer,
+		Width:   state.Width.ValueInt32(),
+		Height:  state.Height.ValueInt32(),
+		X:       state.X.ValueInt32Pointer(),
+		Y:       state.Y.ValueInt32Pointer(),
+		Enabled: state.Enabled.ValueBoolPointer(),
+	}
+
+	b, err := json.Marshal(settings)
+	if err != nil {
+		resp.Diagnostics.AddError("failed to marshal spacer settings", err.Error())
+		return
+	}
+
+	tflog.Info(ctx, "spacer settings", map[string]interface{}{
+		"settings": string(b),
+	})
+
+	state.Json = types.StringValue(string(b))
+
+	stateDiags := resp.State.Set(ctx, &state)
+	resp.Diagnostics.Append(stateDiags...)
+	if resp.Diagnostics.HasError() {
+		return
+	}
+}
+
+func (w spacerDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Context) (CWDashboardBodyWidget, error) {
+	spacer := CWDashboardBodyWidget{
+		Type:   widgetTypeSpacer,
+		Width:  w.Width,
+		Height: widgetHeightOrDefault(w.Height, defaultSpacerHeight),
+	}
+
+	pinWidget(&spacer, w.X, w.Y)
+
+	tflog.Debug(ctx, "built spacer", map[string]interface{}{
+		"widget": spacer,
+	})
+
+	return spacer, nil
+}
@@ -0,0 +1,90 @@
+package provider
+
+import (
+	"context"
+	"testing"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
+	"github.com/tj/assert"
+)
+
+func TestSpacerDataSourceModel_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		model   spacerDataSourceModel
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "valid complete model",
+			model: spacerDataSourceModel{
+				Width:  types.Int32Value(6),
+				Height: types.Int32Value(2),
+				X:      types.Int32Value(18),
+				Y:      types.Int32Value(0),
+			},
+			wantErr: false,
+		},
+		{
+			name:    "valid model with minimum fields",
+			model:   spacerDataSourceModel{},
+			wantErr: false,
+		},
+		{
+			name: "too wide",
+			model: spacerDataSourceModel{
+				Width: types.Int32Value(25),
+			},
+			wantErr: true,
+			errMsg:  "width must be between 1 and 24, got: 25",
+		},
+		{
+			name: "only x",
+			model: spacerDataSourceModel{
+				X: types.Int32Value(0),
+			},
+			wantErr: true,
+			errMsg:  "x and y must be set together",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.model.Validate()
+			if tt.wantErr {
+				if err == nil {
+					t.Errorf("Validate() error = nil, want error %v", tt.errMsg)
+					return
+				}
+				if err.Error() != tt.errMsg {
+					t.Errorf("Validate() error = %v, want %v", err.Error(), tt.errMsg)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("Validate() error = %v, want nil", err)
+			}
+		})
+	}
+}
+
+func TestSpacerDataSourceSettings_ToCWDashboardBodyWidget(t *testing.T) {
+	t.Run("should use the default height", func(t *testing.T) {
+		actual, err := spacerDataSourceSettings{Width: 6}.ToCWDashboardBodyWidget(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, widgetTypeSpacer, actual.Type)
+		assert.Equal(t, int32(6), actual.Width)
+		assert.Equal(t, int32(1), actual.Height)
+		assert.False(t, actual.Pinned)
+	})
+
+	t.Run("should pin the spacer", func(t *testing.T) {
+		actual, err := spacerDataSourceSettings{Width: 6, Height: 4, X: ptr(int32(12)), Y: ptr(int32(2))}.ToCWDashboardBodyWidget(context.Background())
+		require.NoError(t, err)
+
+		assert.Equal(t, widgetPosition{X: 12, Y: 2}, widgetPosition{X: actual.X, Y: actual.Y})
+		assert.True(t, actual.Pinned)
+	})
+}
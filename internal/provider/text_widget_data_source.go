@@ -39,12 +39,24 @@ func (d *textWidgetDataSource) Schema(_ context.Context, _ datasource.SchemaRequ
 				Optional:    true,
 			},
 			"width": schema.Int32Attribute{
-				Description: "The width of the widget",
-				Required:    true,
+				Description: "The width of the widget. Defaults to an even share of its row, see `columns` of `cwdashboard`",
+				Optional:    true,
 			},
 			"height": schema.Int32Attribute{
-				Description: "The height of the widget",
-				Required:    true,
+				Description: "The height of the widget. Defaults to `2`",
+				Optional:    true,
+			},
+			"enabled": schema.BoolAttribute{
+				Description: "Whether the widget is in the dashboard. Defaults to `true`. The other widgets are laid out as if a disabled widget was not there",
+				Optional:    true,
+			},
+			"x": schema.Int32Attribute{
+				Description: "The horizontal position of the widget, in a grid of 24 units wide. Must be set together with `y`. When omitted, the widget is placed automatically",
+				Optional:    true,
+			},
+			"y": schema.Int32Attribute{
+				Description: "The vertical position of the widget. Must be set together with `x`. When omitted, the widget is placed automatically",
+				Optional:    true,
 			},
 
 			"json": schema.StringAttribute{
@@ -60,20 +72,39 @@ type textWidgetDataSourceModel struct {
 	Background types.String `tfsdk:"background"`
 	Width      types.Int32  `tfsdk:"width"`
 	Height     types.Int32  `tfsdk:"height"`
+	X          types.Int32  `tfsdk:"x"`
+	Y          types.Int32  `tfsdk:"y"`
+	Enabled    types.Bool   `tfsdk:"enabled"`
 
 	Json types.String `tfsdk:"json"`
 }
 
+func (d *textWidgetDataSourceModel) Validate() error {
+	if err := validateWidgetSize(d.Width, d.Height); err != nil {
+		return err
+	}
+	if err := validateWidgetPosition(d.X, d.Y); err != nil {
+		return err
+	}
+
+	return nil
+}
+
 type textWidgetDataSourceSettings struct {
 	Type       string `json:"type"`
 	Markdown   string `json:"markdown"`
 	Background string `json:"background"`
 	Width      int32  `json:"width"`
 	Height     int32  `json:"height"`
+	X          *int32 `json:"x,omitempty"`
+	Y          *int32 `json:"y,omitempty"`
+	Enabled    *bool  `json:"enabled,omitempty"`
 }
 
 const (
 	typeTextWidget = "text"
+
+	defaultTextWidgetHeight = 2
 )
 
 func (d *textWidgetDataSource) Read(ctx context.Context, req datasource.ReadRequest, resp *datasource.ReadResponse) {
@@ -84,12 +115,20 @@ func (d *textWidgetDataSource) Read(ctx context.Context, req datasource.ReadRequ
 		return
 	}
 
+	if err := state.Validate(); err != nil {
+		resp.Diagnostics.AddError("invalid settings", err.Error())
+		return
+	}
+
 	settings := textWidgetDataSourceSettings{
 		Type:       typeTextWidget,
 		Markdown:   state.Markdown.ValueString(),
 		Background: state.Background.ValueString(),
 		Width:      state.Width.ValueInt32(),
 		Height:     state.Height.ValueInt32(),
+		X:          state.X.ValueInt32Pointer(),
+		Y:          state.Y.ValueInt32Pointer(),
+		Enabled:    state.Enabled.ValueBoolPointer(),
 	}
 
 	b, err := json.Marshal(settings)
@@ -111,20 +150,18 @@ func (d *textWidgetDataSource) Read(ctx context.Context, req datasource.ReadRequ
 	}
 }
 
-func (w textWidgetDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Context, widget textWidgetDataSourceSettings, beforeWidgetPosition *widgetPosition) (CWDashboardBodyWidget, error) {
+func (w textWidgetDataSourceSettings) ToCWDashboardBodyWidget(ctx context.Context, widget textWidgetDataSourceSettings) (CWDashboardBodyWidget, error) {
 	cwWidget := CWDashboardBodyWidget{
 		Type:   "text",
 		Width:  widget.Width,
-		Height: widget.Height,
+		Height: widgetHeightOrDefault(widget.Height, defaultTextWidgetHeight),
 		Properties: CWDashboardBodyWidgetPropertyText{
 			Markdown:   widget.Markdown,
 			Background: widget.Background,
 		},
 	}
 
-	position := calculatePosition(widgetSize{Width: cwWidget.Width, Height: cwWidget.Height}, beforeWidgetPosition)
-	cwWidget.X = position.X
-	cwWidget.Y = position.Y
+	pinWidget(&cwWidget, widget.X, widget.Y)
 
 	tflog.Debug(ctx, "built text widget", map[string]interface{}{
 		"widget": cwWidget,
@@ -10,10 +10,9 @@ import (
 
 func TestTextWidgetDataSourceSettingsToCWDashboardBodyWidget(t *testing.T) {
 	type testCase struct {
-		name                 string
-		widget               textWidgetDataSourceSettings
-		beforeWidgetPosition *widgetPosition
-		expected             CWDashboardBodyWidget
+		name     string
+		widget   textWidgetDataSourceSettings
+		expected CWDashboardBodyWidget
 	}
 
 	tests := []testCase{
@@ -25,11 +24,8 @@ func TestTextWidgetDataSourceSettingsToCWDashboardBodyWidget(t *testing.T) {
 				Markdown:   "# Test Header",
 				Background: "#ffffff",
 			},
-			beforeWidgetPosition: &widgetPosition{X: 0, Y: 0},
 			expected: CWDashboardBodyWidget{
 				Type:   "text",
-				X:      0,
-				Y:      0,
 				Width:  8,
 				Height: 6,
 				Properties: CWDashboardBodyWidgetPropertyText{
@@ -45,11 +41,8 @@ func TestTextWidgetDataSourceSettingsToCWDashboardBodyWidget(t *testing.T) {
 				Height:   6,
 				Markdown: "# Test Header",
 			},
-			beforeWidgetPosition: &widgetPosition{X: 8, Y: 0},
 			expected: CWDashboardBodyWidget{
 				Type:   "text",
-				X:      8,
-				Y:      0,
 				Width:  8,
 				Height: 6,
 				Properties: CWDashboardBodyWidgetPropertyText{
@@ -58,36 +51,14 @@ func TestTextWidgetDataSourceSettingsToCWDashboardBodyWidget(t *testing.T) {
 			},
 		},
 		{
-			name: "should start from origin when beforeWidgetPosition is nil",
-			widget: textWidgetDataSourceSettings{
-				Width:    8,
-				Height:   6,
-				Markdown: "# Test Header",
-			},
-			beforeWidgetPosition: nil,
-			expected: CWDashboardBodyWidget{
-				Type:   "text",
-				X:      0,
-				Y:      0,
-				Width:  8,
-				Height: 6,
-				Properties: CWDashboardBodyWidgetPropertyText{
-					Markdown: "# Test Header",
-				},
-			},
-		},
-		{
-			name: "should move to next row when exceeding max width",
+			name: "should parse with empty background",
 			widget: textWidgetDataSourceSettings{
 				Width:    8,
 				Height:   6,
 				Markdown: "# Test Header",
 			},
-			beforeWidgetPosition: &widgetPosition{X: 20, Y: 0},
 			expected: CWDashboardBodyWidget{
 				Type:   "text",
-				X:      0,
-				Y:      6,
 				Width:  8,
 				Height: 6,
 				Properties: CWDashboardBodyWidgetPropertyText{
@@ -96,19 +67,14 @@ func TestTextWidgetDataSourceSettingsToCWDashboardBodyWidget(t *testing.T) {
 			},
 		},
 		{
-			name: "should parse with empty background",
+			name: "should use the default height when width and height are omitted",
 			widget: textWidgetDataSourceSettings{
-				Width:    8,
-				Height:   6,
 				Markdown: "# Test Header",
 			},
-			beforeWidgetPosition: &widgetPosition{X: 0, Y: 6},
 			expected: CWDashboardBodyWidget{
 				Type:   "text",
-				X:      0,
-				Y:      6,
-				Width:  8,
-				Height: 6,
+				Width:  0,
+				Height: 2,
 				Properties: CWDashboardBodyWidgetPropertyText{
 					Markdown: "# Test Header",
 				},
@@ -123,12 +89,10 @@ func TestTextWidgetDataSourceSettingsToCWDashboardBodyWidget(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			actual, err := tc.widget.ToCWDashboardBodyWidget(ctx, tc.widget, tc.beforeWidgetPosition)
+			actual, err := tc.widget.ToCWDashboardBodyWidget(ctx, tc.widget)
 			require.NoError(t, err)
 
 			assert.Equal(t, "text", actual.Type)
-			assert.Equal(t, tc.expected.X, actual.X)
-			assert.Equal(t, tc.expected.Y, actual.Y)
 			assert.Equal(t, tc.expected.Width, actual.Width)
 			assert.Equal(t, tc.expected.Height, actual.Height)
 
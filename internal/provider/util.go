@@ -1,5 +1,14 @@
 package provider
 
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/hashicorp/terraform-plugin-framework/types"
+)
+
 type widgetPosition struct {
 	X int32
 	Y int32
@@ -10,27 +19,612 @@ type widgetSize struct {
 	Height int32
 }
 
+type widgetRect struct {
+	widgetPosition
+	widgetSize
+}
+
+func (r widgetRect) overlaps(other widgetRect) bool {
+	return r.X < other.X+other.Width && other.X < r.X+r.Width &&
+		r.Y < other.Y+other.Height && other.Y < r.Y+r.Height
+}
+
 const (
 	MAX_WIDTH = 24
 )
 
-func calculatePosition(size widgetSize, beforeWidgetPosition *widgetPosition) widgetPosition {
-	if beforeWidgetPosition == nil {
-		return widgetPosition{
-			X: 0,
-			Y: 0,
+// validateWidgetSize checks the optional width/height attributes of a widget against the grid of the dashboard.
+func validateWidgetSize(width, height types.Int32) error {
+	if !width.IsNull() && (width.ValueInt32() < 1 || width.ValueInt32() > MAX_WIDTH) {
+		return fmt.Errorf("width must be between 1 and %d, got: %d", MAX_WIDTH, width.ValueInt32())
+	}
+	if !height.IsNull() && height.ValueInt32() < 1 {
+		return fmt.Errorf("height must be greater than 0, got: %d", height.ValueInt32())
+	}
+
+	return nil
+}
+
+// validateWidgetBounds checks the size of a built widget against the grid of the dashboard,
+// for widgets whose settings did not go through validateWidgetSize. A width of 0 is filled in by fillWidgetWidths.
+func validateWidgetBounds(widget CWDashboardBodyWidget) error {
+	if widget.Width < 0 || widget.Width > MAX_WIDTH {
+		return fmt.Errorf("width must be between 1 and %d, got: %d", MAX_WIDTH, widget.Width)
+	}
+	if widget.Height < 0 {
+		return fmt.Errorf("height must be greater than 0, got: %d", widget.Height)
+	}
+
+	return nil
+}
+
+// validateWidgetPosition checks the optional x/y attributes that pin a widget to a position.
+func validateWidgetPosition(x, y types.Int32) error {
+	if x.IsNull() != y.IsNull() {
+		return fmt.Errorf("x and y must be set together")
+	}
+	if x.IsNull() {
+		return nil
+	}
+
+	if x.ValueInt32() < 0 || x.ValueInt32() >= MAX_WIDTH {
+		return fmt.Errorf("x must be between 0 and %d, got: %d", MAX_WIDTH-1, x.ValueInt32())
+	}
+	if y.ValueInt32() < 0 {
+		return fmt.Errorf("y must be greater than or equal to 0, got: %d", y.ValueInt32())
+	}
+
+	return nil
+}
+
+// widgetHeightOrDefault returns the height of a widget, or the default height of its kind when the height is not set.
+func widgetHeightOrDefault(height, defaultHeight int32) int32 {
+	if height == 0 {
+		return defaultHeight
+	}
+
+	return height
+}
+
+// widgetEnabled reports whether a widget with the optional enabled attribute is in the dashboard.
+func widgetEnabled(enabled *bool) bool {
+	return enabled == nil || *enabled
+}
+
+// pinWidget sets the explicit position of a widget, if any, so that the layout keeps it.
+func pinWidget(widget *CWDashboardBodyWidget, x, y *int32) {
+	if x == nil || y == nil {
+		return
+	}
+
+	widget.X = *x
+	widget.Y = *y
+	widget.Pinned = true
+}
+
+// widgetLayout places widgets from left to right, and starts a new row below the tallest widget of the current row
+// when the next widget does not fit. Widgets are flowed around the pinned ones.
+type widgetLayout struct {
+	width     int32
+	cursor    widgetPosition
+	rowHeight int32
+	pinned    []widgetRect
+	// columnHeights is the bottom of every column of the grid, used by placeInShortestColumn
+	columnHeights []int32
+}
+
+func newWidgetLayout(width int32) *widgetLayout {
+	return &widgetLayout{width: width}
+}
+
+func (l *widgetLayout) pin(rect widgetRect) error {
+	if rect.X < 0 || rect.Y < 0 || rect.X+rect.Width > l.width {
+		return fmt.Errorf("widget at x=%d, y=%d with width %d does not fit in the dashboard", rect.X, rect.Y, rect.Width)
+	}
+
+	for _, p := range l.pinned {
+		if p.overlaps(rect) {
+			return fmt.Errorf("widget at x=%d, y=%d overlaps the widget at x=%d, y=%d", rect.X, rect.Y, p.X, p.Y)
+		}
+	}
+
+	l.pinned = append(l.pinned, rect)
+
+	return nil
+}
+
+func (l *widgetLayout) place(size widgetSize) widgetPosition {
+	for {
+		if l.cursor.X > 0 && l.cursor.X+size.Width > l.width {
+			l.nextRow()
+			continue
+		}
+
+		candidate := widgetRect{widgetPosition: l.cursor, widgetSize: size}
+		if blocker, ok := l.findPinnedOverlap(candidate); ok {
+			l.cursor.X = blocker.X + blocker.Width
+			continue
+		}
+
+		break
+	}
+
+	position := l.cursor
+
+	l.cursor.X += size.Width
+	if size.Height > l.rowHeight {
+		l.rowHeight = size.Height
+	}
+
+	return position
+}
+
+func (l *widgetLayout) nextRow() {
+	nextY := l.cursor.Y + l.rowHeight
+
+	// NOTE: when nothing has been placed in the row yet, the row is blocked by pinned widgets,
+	// so it moves down to where the first of them ends
+	if l.rowHeight == 0 {
+		nextY = -1
+		for _, p := range l.pinned {
+			if bottom := p.Y + p.Height; bottom > l.cursor.Y && (nextY == -1 || bottom < nextY) {
+				nextY = bottom
+			}
+		}
+		if nextY == -1 {
+			nextY = l.cursor.Y + 1
+		}
+	}
+
+	l.cursor = widgetPosition{X: 0, Y: nextY}
+	l.rowHeight = 0
+}
+
+func (l *widgetLayout) findPinnedOverlap(rect widgetRect) (widgetRect, bool) {
+	for _, p := range l.pinned {
+		if p.overlaps(rect) {
+			return p, true
+		}
+	}
+
+	return widgetRect{}, false
+}
+
+// placeInShortestColumn places a widget where the columns it covers are the lowest, as in a masonry layout.
+// Pinned widgets are skipped by moving below them.
+func (l *widgetLayout) placeInShortestColumn(size widgetSize) widgetPosition {
+	if l.columnHeights == nil {
+		l.columnHeights = make([]int32, l.width)
+	}
+
+	best := widgetPosition{Y: -1}
+	for x := int32(0); x == 0 || x+size.Width <= l.width; x++ {
+		var y int32
+		for c := x; c < x+size.Width && c < l.width; c++ {
+			y = max(y, l.columnHeights[c])
+		}
+		for {
+			blocker, ok := l.findPinnedOverlap(widgetRect{widgetPosition: widgetPosition{X: x, Y: y}, widgetSize: size})
+			if !ok {
+				break
+			}
+			y = blocker.Y + blocker.Height
+		}
+
+		if best.Y == -1 || y < best.Y {
+			best = widgetPosition{X: x, Y: y}
+		}
+	}
+
+	for c := best.X; c < best.X+size.Width && c < l.width; c++ {
+		l.columnHeights[c] = best.Y + size.Height
+	}
+
+	return best
+}
+
+const (
+	// layoutModeFlow places widgets from left to right, starting a new row below the tallest widget of the row
+	layoutModeFlow = "flow"
+	// layoutModeCompact flows the widgets, and then moves each of them up as far as it can go
+	layoutModeCompact = "compact"
+	// layoutModeMasonry places each widget where the columns it covers are the lowest
+	layoutModeMasonry = "masonry"
+)
+
+// layoutWidgets keeps the position of the pinned widgets and sets the position of the others in the given order,
+// following the layout mode.
+func layoutWidgets(widgets []CWDashboardBodyWidget, mode string) error {
+	layout := newWidgetLayout(MAX_WIDTH)
+	if err := pinWidgets(layout, widgets); err != nil {
+		return err
+	}
+
+	for i := range widgets {
+		if widgets[i].Pinned {
+			continue
+		}
+
+		size := widgetSize{Width: widgets[i].Width, Height: widgets[i].Height}
+		var position widgetPosition
+		if mode == layoutModeMasonry {
+			position = layout.placeInShortestColumn(size)
+		} else {
+			position = layout.place(size)
+		}
+		widgets[i].X = position.X
+		widgets[i].Y = position.Y
+	}
+
+	if mode == layoutModeCompact {
+		compactWidgets(widgets)
+	}
+
+	return nil
+}
+
+// pinWidgets takes the cells of the pinned widgets in the layout, failing when a widget does not fit or overlaps another one.
+func pinWidgets(layout *widgetLayout, widgets []CWDashboardBodyWidget) error {
+	for _, w := range widgets {
+		if !w.Pinned {
+			continue
+		}
+		rect := widgetRect{
+			widgetPosition: widgetPosition{X: w.X, Y: w.Y},
+			widgetSize:     widgetSize{Width: w.Width, Height: w.Height},
+		}
+		if err := layout.pin(rect); err != nil {
+			return &widgetError{Index: w.Index, Err: fmt.Errorf("invalid position: %w", err)}
+		}
+	}
+
+	return nil
+}
+
+// compactWidgets moves every widget that is not pinned up until it reaches the top or another widget,
+// closing the holes as the CloudWatch console does. Widgets higher up are moved first.
+func compactWidgets(widgets []CWDashboardBodyWidget) {
+	order := make([]int, 0, len(widgets))
+	for i, w := range widgets {
+		if !w.Pinned {
+			order = append(order, i)
+		}
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		wa, wb := widgets[order[a]], widgets[order[b]]
+		if wa.Y != wb.Y {
+			return wa.Y < wb.Y
+		}
+		return wa.X < wb.X
+	})
+
+	rectOf := func(w CWDashboardBodyWidget) widgetRect {
+		return widgetRect{widgetPosition: widgetPosition{X: w.X, Y: w.Y}, widgetSize: widgetSize{Width: w.Width, Height: w.Height}}
+	}
+
+	for _, i := range order {
+		for widgets[i].Y > 0 {
+			candidate := rectOf(widgets[i])
+			candidate.Y--
+
+			blocked := false
+			for j, other := range widgets {
+				if j != i && candidate.overlaps(rectOf(other)) {
+					blocked = true
+					break
+				}
+			}
+			if blocked {
+				break
+			}
+			widgets[i].Y = candidate.Y
+		}
+	}
+}
+
+const (
+	widgetGroupTypeRow     = "row"
+	widgetGroupTypeColumn  = "column"
+	widgetGroupTypeSection = "section"
+
+	// widgetTypeSpacer is a layout-only widget, which takes up cells of the grid but is removed from the dashboard
+	widgetTypeSpacer = "spacer"
+)
+
+// resizeWidget sets the width of a widget, and arranges the children again when the widget is a row, a column or a section.
+func resizeWidget(widget *CWDashboardBodyWidget, width int32) error {
+	widget.Width = width
+
+	switch widget.Type {
+	case widgetGroupTypeRow:
+		return arrangeRow(widget)
+	case widgetGroupTypeColumn:
+		return arrangeColumn(widget)
+	case widgetGroupTypeSection:
+		return arrangeSection(widget)
+	}
+
+	return nil
+}
+
+// arrangeRow splits the width of the row evenly among its children and places them side by side.
+// The height of the row is the height of its tallest child.
+func arrangeRow(row *CWDashboardBodyWidget) error {
+	count := int32(len(row.Children))
+	if count == 0 {
+		return fmt.Errorf("row must contain at least one widget")
+	}
+	if count > row.Width {
+		return fmt.Errorf("row of width %d cannot hold %d widgets", row.Width, count)
+	}
+
+	// NOTE: the remainder of the division is given to the first children, so the row is filled without a gap
+	width, remainder := row.Width/count, row.Width%count
+
+	var x, height int32
+	for i := range row.Children {
+		childWidth := width
+		if int32(i) < remainder {
+			childWidth++
+		}
+		if err := resizeWidget(&row.Children[i], childWidth); err != nil {
+			return err
+		}
+		row.Children[i].X = x
+		row.Children[i].Y = 0
+
+		x += childWidth
+		if row.Children[i].Height > height {
+			height = row.Children[i].Height
+		}
+	}
+	row.Height = height
+
+	return nil
+}
+
+// arrangeColumn stacks the children of the column, each taking the full width of the column.
+func arrangeColumn(column *CWDashboardBodyWidget) error {
+	if len(column.Children) == 0 {
+		return fmt.Errorf("column must contain at least one widget")
+	}
+
+	var y int32
+	for i := range column.Children {
+		if err := resizeWidget(&column.Children[i], column.Width); err != nil {
+			return err
+		}
+		column.Children[i].X = 0
+		column.Children[i].Y = y
+
+		y += column.Children[i].Height
+	}
+	column.Height = y
+
+	return nil
+}
+
+// arrangeSection places the header of the section at the top, and flows the other children below it
+// in the width of the section.
+func arrangeSection(section *CWDashboardBodyWidget) error {
+	if len(section.Children) < 2 {
+		return fmt.Errorf("section must contain at least one widget")
+	}
+
+	header := &section.Children[0]
+	header.X = 0
+	header.Y = 0
+	header.Width = section.Width
+
+	layout := newWidgetLayout(section.Width)
+	layout.cursor.Y = header.Height
+
+	height := header.Height
+	for i := 1; i < len(section.Children); i++ {
+		child := &section.Children[i]
+		if child.Width == 0 || child.Width > section.Width {
+			if err := resizeWidget(child, section.Width); err != nil {
+				return err
+			}
+		}
+
+		position := layout.place(widgetSize{Width: child.Width, Height: child.Height})
+		child.X = position.X
+		child.Y = position.Y
+
+		if bottom := child.Y + child.Height; bottom > height {
+			height = bottom
+		}
+	}
+	section.Height = height
+
+	return nil
+}
+
+// fillWidgetWidths gives the widgets without a width an even share of the width left in their row.
+// The widgets that are not pinned are split into rows as the layout does: a row has at most `columns` widgets,
+// and a widget starts a new row when it does not fit in the width left, leaving at least 1 unit to each widget without a width.
+// Pinned widgets without a width take the width of a column.
+// NOTE: the rows follow the flow order and leave out the pinned widgets, so they may differ from where the layout places
+// the widgets around the pinned ones, or in the compact and masonry modes
+func fillWidgetWidths(widgets []CWDashboardBodyWidget, columns int32) error {
+	var rows [][]int
+	var row []int
+	used, unsized := int32(0), int32(0)
+	for i := range widgets {
+		if widgets[i].Pinned {
+			if widgets[i].Width == 0 {
+				if err := resizeWidget(&widgets[i], MAX_WIDTH/columns); err != nil {
+					return &widgetError{Index: widgets[i].Index, Err: err}
+				}
+			}
+			continue
+		}
+
+		// a widget without a width needs at least 1 unit
+		width := max(widgets[i].Width, 1)
+		if len(row) > 0 && (int32(len(row)) >= columns || used+unsized+width > MAX_WIDTH) {
+			rows = append(rows, row)
+			row, used, unsized = nil, 0, 0
+		}
+
+		row = append(row, i)
+		if widgets[i].Width == 0 {
+			unsized++
+		} else {
+			used += widgets[i].Width
+		}
+	}
+	if len(row) > 0 {
+		rows = append(rows, row)
+	}
+
+	for _, row := range rows {
+		remaining := int32(MAX_WIDTH)
+		unsized := make([]int, 0, len(row))
+		for _, i := range row {
+			if widgets[i].Width == 0 {
+				unsized = append(unsized, i)
+			} else {
+				remaining -= widgets[i].Width
+			}
+		}
+		if len(unsized) == 0 {
+			continue
+		}
+
+		share, extra := remaining/int32(len(unsized)), remaining%int32(len(unsized))
+		for k, i := range unsized {
+			width := share
+			if int32(k) < extra {
+				width++
+			}
+			if err := resizeWidget(&widgets[i], width); err != nil {
+				return &widgetError{Index: widgets[i].Index, Err: err}
+			}
+		}
+	}
+
+	return nil
+}
+
+// flattenWidgets replaces rows, columns and sections with their children, moving them to the position of the group.
+func flattenWidgets(widgets []CWDashboardBodyWidget) []CWDashboardBodyWidget {
+	flattened := make([]CWDashboardBodyWidget, 0, len(widgets))
+	for _, w := range widgets {
+		if w.Type != widgetGroupTypeRow && w.Type != widgetGroupTypeColumn && w.Type != widgetGroupTypeSection {
+			flattened = append(flattened, w)
+			continue
+		}
+
+		children := make([]CWDashboardBodyWidget, len(w.Children))
+		for i, child := range w.Children {
+			child.X += w.X
+			child.Y += w.Y
+			children[i] = child
+		}
+		flattened = append(flattened, flattenWidgets(children)...)
+	}
+
+	return flattened
+}
+
+const (
+	// layoutTemplateEmptyCell marks a cell of the layout template that no widget covers
+	layoutTemplateEmptyCell = '.'
+)
+
+// layoutTemplateRegion is the area of the dashboard covered by a letter of the layout template.
+type layoutTemplateRegion struct {
+	Letter string
+	Rect   widgetRect
+}
+
+// parseLayoutTemplate converts an ASCII-art grid such as "AAAB\nCCDD" into the regions of its letters,
+// in the order they first appear. The columns of the template split the width of the dashboard evenly,
+// and every line of the template is rowHeight units high.
+func parseLayoutTemplate(template string, rowHeight int32) ([]layoutTemplateRegion, error) {
+	lines := make([][]rune, 0)
+	for _, line := range strings.Split(template, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" {
+			continue
 		}
+		lines = append(lines, []rune(line))
+	}
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("layout cannot be empty")
+	}
+
+	columns := len(lines[0])
+	if columns > MAX_WIDTH {
+		return nil, fmt.Errorf("layout can have at most %d columns, got: %d", MAX_WIDTH, columns)
 	}
 
-	if beforeWidgetPosition.X+size.Width > MAX_WIDTH {
-		return widgetPosition{
-			X: 0,
-			Y: beforeWidgetPosition.Y + size.Height,
+	type bounds struct {
+		top, left, bottom, right, cells int
+	}
+	letters := make([]rune, 0)
+	found := make(map[rune]*bounds)
+	for y, line := range lines {
+		if len(line) != columns {
+			return nil, fmt.Errorf("line %d of layout has %d columns, expected %d", y+1, len(line), columns)
 		}
+
+		for x, c := range line {
+			if c == layoutTemplateEmptyCell {
+				continue
+			}
+			if !unicode.IsLetter(c) {
+				return nil, fmt.Errorf("invalid character %q at line %d of layout", c, y+1)
+			}
+
+			b, ok := found[c]
+			if !ok {
+				b = &bounds{top: y, left: x, bottom: y, right: x}
+				found[c] = b
+				letters = append(letters, c)
+			}
+			b.left = min(b.left, x)
+			b.right = max(b.right, x)
+			b.bottom = y
+			b.cells++
+		}
+	}
+
+	// NOTE: the columns are mapped onto the grid of the dashboard, so that templates which do not divide it evenly still cover it
+	columnX := func(column int) int32 {
+		return int32(column * MAX_WIDTH / columns)
+	}
+
+	regions := make([]layoutTemplateRegion, 0, len(letters))
+	for _, letter := range letters {
+		b := found[letter]
+		if (b.bottom-b.top+1)*(b.right-b.left+1) != b.cells {
+			return nil, fmt.Errorf("region of %q in layout is not a rectangle", letter)
+		}
+
+		regions = append(regions, layoutTemplateRegion{
+			Letter: string(letter),
+			Rect: widgetRect{
+				widgetPosition: widgetPosition{X: columnX(b.left), Y: int32(b.top) * rowHeight},
+				widgetSize:     widgetSize{Width: columnX(b.right+1) - columnX(b.left), Height: int32(b.bottom-b.top+1) * rowHeight},
+			},
+		})
 	}
 
-	return widgetPosition{
-		X: beforeWidgetPosition.X,
-		Y: beforeWidgetPosition.Y,
+	return regions, nil
+}
+
+// removeSpacers drops the spacers once the layout is done, as they only hold space in the grid.
+func removeSpacers(widgets []CWDashboardBodyWidget) []CWDashboardBodyWidget {
+	kept := make([]CWDashboardBodyWidget, 0, len(widgets))
+	for _, w := range widgets {
+		if w.Type == widgetTypeSpacer {
+			continue
+		}
+		kept = append(kept, w)
 	}
+
+	return kept
 }
@@ -3,50 +3,72 @@ package provider
 import (
 	"testing"
 
+	"github.com/hashicorp/terraform-plugin-framework/types"
+	"github.com/stretchr/testify/require"
 	"github.com/tj/assert"
 )
 
-func TestCalculatePosition(t *testing.T) {
+func TestWidgetLayout_Place(t *testing.T) {
 	tests := []struct {
-		name                 string
-		size                 widgetSize
-		beforeWidgetPosition *widgetPosition
-		expected             widgetPosition
+		name     string
+		sizes    []widgetSize
+		expected []widgetPosition
 	}{
 		{
-			name: "should start from origin when beforeWidgetPosition is nil",
-			size: widgetSize{
-				Width:  8,
-				Height: 6,
+			name:     "should start from origin",
+			sizes:    []widgetSize{{Width: 8, Height: 6}},
+			expected: []widgetPosition{{X: 0, Y: 0}},
+		},
+		{
+			name: "should place widgets side by side in the same row",
+			sizes: []widgetSize{
+				{Width: 8, Height: 6},
+				{Width: 8, Height: 6},
+				{Width: 8, Height: 6},
 			},
-			beforeWidgetPosition: nil,
-			expected: widgetPosition{
-				X: 0,
-				Y: 0,
+			expected: []widgetPosition{
+				{X: 0, Y: 0},
+				{X: 8, Y: 0},
+				{X: 16, Y: 0},
 			},
 		},
 		{
-			name: "should calculate next position in same row",
-			size: widgetSize{
-				Width:  8,
-				Height: 6,
+			name: "should move to next row when exceeding max width",
+			sizes: []widgetSize{
+				{Width: 20, Height: 6},
+				{Width: 5, Height: 6},
 			},
-			beforeWidgetPosition: &widgetPosition{X: 0, Y: 0},
-			expected: widgetPosition{
-				X: 0,
-				Y: 0,
+			expected: []widgetPosition{
+				{X: 0, Y: 0},
+				{X: 0, Y: 6},
 			},
 		},
 		{
-			name: "should move to next row when exceeding max width",
-			size: widgetSize{
-				Width:  5,
-				Height: 6,
+			name: "should start next row below the tallest widget of the row",
+			sizes: []widgetSize{
+				{Width: 8, Height: 3},
+				{Width: 8, Height: 9},
+				{Width: 8, Height: 6},
+				{Width: 12, Height: 2},
 			},
-			beforeWidgetPosition: &widgetPosition{X: 20, Y: 0},
-			expected: widgetPosition{
-				X: 0,
-				Y: 6,
+			expected: []widgetPosition{
+				{X: 0, Y: 0},
+				{X: 8, Y: 0},
+				{X: 16, Y: 0},
+				{X: 0, Y: 9},
+			},
+		},
+		{
+			name: "should not leave an empty row for a full width widget",
+			sizes: []widgetSize{
+				{Width: 24, Height: 4},
+				{Width: 24, Height: 2},
+				{Width: 6, Height: 3},
+			},
+			expected: []widgetPosition{
+				{X: 0, Y: 0},
+				{X: 0, Y: 4},
+				{X: 0, Y: 6},
 			},
 		},
 	}
@@ -56,8 +78,470 @@ func TestCalculatePosition(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			t.Parallel()
 
-			actual := calculatePosition(tc.size, tc.beforeWidgetPosition)
+			layout := newWidgetLayout(MAX_WIDTH)
+			actual := make([]widgetPosition, 0, len(tc.sizes))
+			for _, size := range tc.sizes {
+				actual = append(actual, layout.place(size))
+			}
 			assert.Equal(t, tc.expected, actual)
 		})
 	}
 }
+
+func TestLayoutWidgets(t *testing.T) {
+	widgets := []CWDashboardBodyWidget{
+		{Type: "text", Width: 12, Height: 2},
+		{Type: "metric", Width: 12, Height: 6},
+		{Type: "metric", Width: 24, Height: 6},
+	}
+
+	assert.NoError(t, layoutWidgets(widgets, layoutModeFlow))
+
+	assert.Equal(t, []widgetPosition{
+		{X: 0, Y: 0},
+		{X: 12, Y: 0},
+		{X: 0, Y: 6},
+	}, []widgetPosition{
+		{X: widgets[0].X, Y: widgets[0].Y},
+		{X: widgets[1].X, Y: widgets[1].Y},
+		{X: widgets[2].X, Y: widgets[2].Y},
+	})
+}
+
+func TestLayoutWidgets_Pinned(t *testing.T) {
+	tests := []struct {
+		name     string
+		widgets  []CWDashboardBodyWidget
+		expected []widgetPosition
+		errMsg   string
+	}{
+		{
+			name: "should keep pinned widgets and flow the others around them",
+			widgets: []CWDashboardBodyWidget{
+				{Width: 6, Height: 4, X: 18, Y: 0, Pinned: true},
+				{Width: 12, Height: 6},
+				{Width: 12, Height: 6},
+				{Width: 6, Height: 2},
+			},
+			expected: []widgetPosition{
+				{X: 18, Y: 0},
+				{X: 0, Y: 0},
+				{X: 0, Y: 6},
+				{X: 12, Y: 6},
+			},
+		},
+		{
+			name: "should skip a full width pinned widget",
+			widgets: []CWDashboardBodyWidget{
+				{Width: 8, Height: 6},
+				{Width: 24, Height: 2, X: 0, Y: 6, Pinned: true},
+				{Width: 24, Height: 6},
+			},
+			expected: []widgetPosition{
+				{X: 0, Y: 0},
+				{X: 0, Y: 6},
+				{X: 0, Y: 8},
+			},
+		},
+		{
+			name: "should move below pinned widgets blocking the first row",
+			widgets: []CWDashboardBodyWidget{
+				{Width: 12, Height: 3, X: 0, Y: 0, Pinned: true},
+				{Width: 12, Height: 5, X: 12, Y: 0, Pinned: true},
+				{Width: 12, Height: 6},
+			},
+			expected: []widgetPosition{
+				{X: 0, Y: 0},
+				{X: 12, Y: 0},
+				{X: 0, Y: 3},
+			},
+		},
+		{
+			name: "should fail when pinned widgets overlap",
+			widgets: []CWDashboardBodyWidget{
+				{Width: 12, Height: 6, X: 0, Y: 0, Pinned: true},
+				{Width: 12, Height: 6, X: 6, Y: 3, Pinned: true, Index: 1},
+			},
+			errMsg: "invalid widget at index 1: invalid position: widget at x=6, y=3 overlaps the widget at x=0, y=0",
+		},
+		{
+			name: "should fail when a pinned widget exceeds the width of the dashboard",
+			widgets: []CWDashboardBodyWidget{
+				{Width: 12, Height: 6, X: 18, Y: 0, Pinned: true},
+			},
+			errMsg: "invalid widget at index 0: invalid position: widget at x=18, y=0 with width 12 does not fit in the dashboard",
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			err := layoutWidgets(tc.widgets, layoutModeFlow)
+			if tc.errMsg != "" {
+				assert.EqualError(t, err, tc.errMsg)
+				return
+			}
+			assert.NoError(t, err)
+
+			actual := make([]widgetPosition, 0, len(tc.widgets))
+			for _, w := range tc.widgets {
+				actual = append(actual, widgetPosition{X: w.X, Y: w.Y})
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestLayoutWidgets_Modes(t *testing.T) {
+	tests := []struct {
+		name     string
+		mode     string
+		widgets  []CWDashboardBodyWidget
+		expected []widgetPosition
+	}{
+		{
+			name: "compact should move widgets up into the free space",
+			mode: layoutModeCompact,
+			widgets: []CWDashboardBodyWidget{
+				{Width: 12, Height: 2},
+				{Width: 12, Height: 6},
+				{Width: 12, Height: 6},
+			},
+			expected: []widgetPosition{
+				{X: 0, Y: 0},
+				{X: 12, Y: 0},
+				{X: 0, Y: 2},
+			},
+		},
+		{
+			name: "compact should keep pinned widgets",
+			mode: layoutModeCompact,
+			widgets: []CWDashboardBodyWidget{
+				{Width: 24, Height: 2, X: 0, Y: 10, Pinned: true},
+				{Width: 12, Height: 2},
+				{Width: 12, Height: 6},
+				{Width: 12, Height: 4},
+			},
+			expected: []widgetPosition{
+				{X: 0, Y: 10},
+				{X: 0, Y: 0},
+				{X: 12, Y: 0},
+				{X: 0, Y: 2},
+			},
+		},
+		{
+			name: "masonry should place each widget in the shortest column",
+			mode: layoutModeMasonry,
+			widgets: []CWDashboardBodyWidget{
+				{Width: 12, Height: 2},
+				{Width: 12, Height: 6},
+				{Width: 12, Height: 4},
+				{Width: 12, Height: 3},
+			},
+			expected: []widgetPosition{
+				{X: 0, Y: 0},
+				{X: 12, Y: 0},
+				{X: 0, Y: 2},
+				{X: 0, Y: 6},
+			},
+		},
+		{
+			name: "masonry should move below pinned widgets",
+			mode: layoutModeMasonry,
+			widgets: []CWDashboardBodyWidget{
+				{Width: 12, Height: 4, X: 0, Y: 0, Pinned: true},
+				{Width: 12, Height: 6},
+				{Width: 12, Height: 2},
+			},
+			expected: []widgetPosition{
+				{X: 0, Y: 0},
+				{X: 12, Y: 0},
+				{X: 0, Y: 4},
+			},
+		},
+	}
+
+	for _, tc := range tests {
+		tc := tc
+		t.Run(tc.name, func(t *testing.T) {
+			t.Parallel()
+
+			assert.NoError(t, layoutWidgets(tc.widgets, tc.mode))
+
+			actual := make([]widgetPosition, 0, len(tc.widgets))
+			for _, w := range tc.widgets {
+				actual = append(actual, widgetPosition{X: w.X, Y: w.Y})
+			}
+			assert.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
+func TestFillWidgetWidths(t *testing.T) {
+	t.Run("should split the width left in each row among the widgets without a width", func(t *testing.T) {
+		widgets := []CWDashboardBodyWidget{
+			{Width: 6, Height: 2},
+			{Height: 6},
+			{Height: 6},
+			{Width: 2, Height: 4, X: 22, Y: 20, Pinned: true},
+			{Height: 6},
+		}
+
+		require.NoError(t, fillWidgetWidths(widgets, 3))
+
+		widths := make([]int32, 0, len(widgets))
+		for _, w := range widgets {
+			widths = append(widths, w.Width)
+		}
+		assert.Equal(t, []int32{6, 9, 9, 2, 24}, widths)
+	})
+
+	t.Run("should give the remainder of the width to the first widgets", func(t *testing.T) {
+		widgets := []CWDashboardBodyWidget{
+			{Height: 6},
+			{Height: 6},
+			{Height: 6},
+			{Height: 6},
+			{Height: 6},
+		}
+
+		require.NoError(t, fillWidgetWidths(widgets, 5))
+
+		widths := make([]int32, 0, len(widgets))
+		for _, w := range widgets {
+			widths = append(widths, w.Width)
+		}
+		assert.Equal(t, []int32{5, 5, 5, 5, 4}, widths)
+	})
+
+	t.Run("should arrange a column without a width", func(t *testing.T) {
+		widgets := []CWDashboardBodyWidget{
+			{
+				Type: widgetGroupTypeColumn,
+				Children: []CWDashboardBodyWidget{
+					{Height: 2},
+					{Height: 6},
+				},
+			},
+			{Width: 12, Height: 6},
+		}
+
+		require.NoError(t, fillWidgetWidths(widgets, 2))
+
+		assert.Equal(t, int32(12), widgets[0].Width)
+		assert.Equal(t, int32(8), widgets[0].Height)
+		assert.Equal(t, int32(12), widgets[0].Children[1].Width)
+		assert.Equal(t, int32(2), widgets[0].Children[1].Y)
+	})
+
+	t.Run("should start a new row at a widget which does not fit in the row", func(t *testing.T) {
+		widgets := []CWDashboardBodyWidget{
+			{Width: 24, Height: 1},
+			{Height: 6},
+			{Height: 6},
+			{Height: 6},
+			{Width: 20, Height: 6},
+			{Height: 6},
+			{Width: 12, Height: 6},
+		}
+
+		require.NoError(t, fillWidgetWidths(widgets, 3))
+
+		widths := make([]int32, 0, len(widgets))
+		for _, w := range widgets {
+			widths = append(widths, w.Width)
+		}
+		// the header fills its row, and the widget of 12 units does not fit next to the one of 20
+		assert.Equal(t, []int32{24, 8, 8, 8, 20, 4, 12}, widths)
+	})
+
+	t.Run("should point at the widget which cannot be given the width", func(t *testing.T) {
+		widgets := []CWDashboardBodyWidget{
+			{Width: 23, Height: 6, Index: 1},
+			{
+				Type: widgetGroupTypeRow,
+				Children: []CWDashboardBodyWidget{
+					{Height: 2},
+					{Height: 2},
+				},
+				Index: 3,
+			},
+		}
+
+		err := fillWidgetWidths(widgets, 2)
+		assert.EqualError(t, err, "invalid widget at index 3: row of width 1 cannot hold 2 widgets")
+	})
+}
+
+func TestValidateWidgetSize(t *testing.T) {
+	tests := []struct {
+		name   string
+		width  types.Int32
+		height types.Int32
+		errMsg string
+	}{
+		{
+			name:   "not set",
+			width:  types.Int32Null(),
+			height: types.Int32Null(),
+		},
+		{
+			name:   "full width",
+			width:  types.Int32Value(24),
+			height: types.Int32Value(1),
+		},
+		{
+			name:   "zero width",
+			width:  types.Int32Value(0),
+			height: types.Int32Null(),
+			errMsg: "width must be between 1 and 24, got: 0",
+		},
+		{
+			name:   "too wide",
+			width:  types.Int32Value(25),
+			height: types.Int32Value(6),
+			errMsg: "width must be between 1 and 24, got: 25",
+		},
+		{
+			name:   "negative height",
+			width:  types.Int32Value(12),
+			height: types.Int32Value(-1),
+			errMsg: "height must be greater than 0, got: -1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateWidgetSize(tc.width, tc.height)
+			if tc.errMsg != "" {
+				assert.EqualError(t, err, tc.errMsg)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestValidateWidgetPosition(t *testing.T) {
+	tests := []struct {
+		name   string
+		x      types.Int32
+		y      types.Int32
+		errMsg string
+	}{
+		{
+			name: "not pinned",
+			x:    types.Int32Null(),
+			y:    types.Int32Null(),
+		},
+		{
+			name: "pinned",
+			x:    types.Int32Value(18),
+			y:    types.Int32Value(0),
+		},
+		{
+			name:   "only x",
+			x:      types.Int32Value(18),
+			y:      types.Int32Null(),
+			errMsg: "x and y must be set together",
+		},
+		{
+			name:   "x out of the grid",
+			x:      types.Int32Value(24),
+			y:      types.Int32Value(0),
+			errMsg: "x must be between 0 and 23, got: 24",
+		},
+		{
+			name:   "negative y",
+			x:      types.Int32Value(0),
+			y:      types.Int32Value(-1),
+			errMsg: "y must be greater than or equal to 0, got: -1",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			err := validateWidgetPosition(tc.x, tc.y)
+			if tc.errMsg != "" {
+				assert.EqualError(t, err, tc.errMsg)
+				return
+			}
+			assert.NoError(t, err)
+		})
+	}
+}
+
+func TestParseLayoutTemplate(t *testing.T) {
+	t.Run("should derive the rectangle of every letter", func(t *testing.T) {
+		actual, err := parseLayoutTemplate("\n  AAAB\n  CCDD\n  CC..\n", 6)
+		require.NoError(t, err)
+
+		assert.Equal(t, []layoutTemplateRegion{
+			{Letter: "A", Rect: widgetRect{widgetPosition{X: 0, Y: 0}, widgetSize{Width: 18, Height: 6}}},
+			{Letter: "B", Rect: widgetRect{widgetPosition{X: 18, Y: 0}, widgetSize{Width: 6, Height: 6}}},
+			{Letter: "C", Rect: widgetRect{widgetPosition{X: 0, Y: 6}, widgetSize{Width: 12, Height: 12}}},
+			{Letter: "D", Rect: widgetRect{widgetPosition{X: 12, Y: 6}, widgetSize{Width: 12, Height: 6}}},
+		}, actual)
+	})
+
+	t.Run("should cover the dashboard when the columns do not divide it evenly", func(t *testing.T) {
+		actual, err := parseLayoutTemplate("ABCDE", 3)
+		require.NoError(t, err)
+
+		widths := make([]int32, 0, len(actual))
+		for _, r := range actual {
+			widths = append(widths, r.Rect.Width)
+		}
+		assert.Equal(t, []int32{4, 5, 5, 5, 5}, widths)
+	})
+
+	tests := []struct {
+		name     string
+		template string
+		errMsg   string
+	}{
+		{
+			name:     "empty",
+			template: " \n ",
+			errMsg:   "layout cannot be empty",
+		},
+		{
+			name:     "lines of different lengths",
+			template: "AAB\nCC",
+			errMsg:   "line 2 of layout has 2 columns, expected 3",
+		},
+		{
+			name:     "too many columns",
+			template: "AAAAAAAAAAAAAAAAAAAAAAAAA",
+			errMsg:   "layout can have at most 24 columns, got: 25",
+		},
+		{
+			name:     "invalid character",
+			template: "AB\nA#",
+			errMsg:   "invalid character '#' at line 2 of layout",
+		},
+		{
+			name:     "not a rectangle",
+			template: "AAB\nABB",
+			errMsg:   "region of 'A' in layout is not a rectangle",
+		},
+		{
+			name:     "split region",
+			template: "ABA",
+			errMsg:   "region of 'A' in layout is not a rectangle",
+		},
+	}
+
+	for _, tc := range tests {
+		t.Run(tc.name, func(t *testing.T) {
+			_, err := parseLayoutTemplate(tc.template, 6)
+			assert.EqualError(t, err, tc.errMsg)
+		})
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}